@@ -6,28 +6,66 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"regexp"
 	"strings"
 
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
+// Template is a cloudformation template, either a body read from a file or url, or the url of
+// a template in S3 that is passed through to cloudformation
+type Template struct {
+	Body string
+	URL  string
+}
+
+// IsEmpty returns true if no template was provided
+func (t *Template) IsEmpty() bool {
+	return t.Body == "" && t.URL == ""
+}
+
 // TemplateSourceValue is a Kingpin type for either a local file or a remote url
-type TemplateSourceValue bytes.Buffer
+type TemplateSourceValue Template
 
 func (h *TemplateSourceValue) Set(value string) error {
+	if IsS3URL(value) {
+		log.Printf("Using template url %s", value)
+		h.URL = value
+		return nil
+	}
+
 	log.Printf("Read from %s", value)
+	buf := &bytes.Buffer{}
 	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
-		return readURL(value, (*bytes.Buffer)(h))
+		if err := readURL(value, buf); err != nil {
+			return err
+		}
+		h.Body = buf.String()
+		return nil
 	}
 	f, err := os.Open(value)
 	if err == nil {
 		defer f.Close()
-		io.Copy((*bytes.Buffer)(h), f)
+		io.Copy(buf, f)
+		h.Body = buf.String()
 	}
 	return err
 }
 
+var s3HostPattern = regexp.MustCompile(`(^|\.)s3([.-][a-z0-9-]+)*\.amazonaws\.com(\.cn)?$`)
+
+// IsS3URL returns whether a url is an https url of an object in S3, which cloudformation can
+// read directly as a TemplateURL
+func IsS3URL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme != "https" {
+		return false
+	}
+	return s3HostPattern.MatchString(u.Host)
+}
+
 func readURL(u string, w io.Writer) error {
 	resp, err := http.Get(u)
 	if err != nil {
@@ -47,8 +85,8 @@ func (h TemplateSourceValue) String() string {
 	return "[bytes]"
 }
 
-func TemplateSource(s kingpin.Settings) (target *bytes.Buffer) {
-	target = &bytes.Buffer{}
+func TemplateSource(s kingpin.Settings) (target *Template) {
+	target = &Template{}
 	s.SetValue((*TemplateSourceValue)(target))
 	return
 }
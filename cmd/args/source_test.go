@@ -0,0 +1,28 @@
+package args
+
+import "testing"
+
+func TestDetectingS3URLs(t *testing.T) {
+	for _, u := range []string{
+		"https://s3.amazonaws.com/my-bucket/stack.json",
+		"https://s3-ap-southeast-2.amazonaws.com/my-bucket/stack.json",
+		"https://s3.eu-west-1.amazonaws.com/my-bucket/stack.json",
+		"https://my-bucket.s3.amazonaws.com/stack.json",
+		"https://my-bucket.s3-us-west-2.amazonaws.com/stack.json",
+	} {
+		if !IsS3URL(u) {
+			t.Errorf("Expected %s to be an S3 url", u)
+		}
+	}
+
+	for _, u := range []string{
+		"http://s3.amazonaws.com/my-bucket/stack.json",
+		"https://example.com/stack.json",
+		"https://s3.amazonaws.com.example.com/stack.json",
+		"stack.json",
+	} {
+		if IsS3URL(u) {
+			t.Errorf("Expected %s not to be an S3 url", u)
+		}
+	}
+}
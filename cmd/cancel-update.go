@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureCancelUpdate(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName string
+
+	cmd := app.Command("cancel-update", "Cancel an in-progress update of a cloudformation stack and watch the rollback")
+	cmd.Alias("cancel")
+
+	events := addEventFlags(cmd)
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		t := time.Now()
+		svc := cloudformation.New(sess)
+
+		if err := stacks.CancelUpdate(svc, stackName); err != nil {
+			return err
+		}
+
+		return watchRollback(svc, stackName, t, events)
+	})
+}
+
+// watchRollback polls a stack's events until a rollback finishes, returning an error if the
+// stack isn't in UPDATE_ROLLBACK_COMPLETE at the end
+func watchRollback(svc *cloudformation.CloudFormation, stackName string, t time.Time, events *eventFlags) error {
+	printer := events.Printer(stackName, t)
+	err := stacks.WaitForOperation(svc, stackName, printer.Print)
+	printer.Stop()
+
+	// a cancelled update is meant to end in UPDATE_ROLLBACK_COMPLETE
+	if opErr, ok := err.(*stacks.OperationError); ok && opErr.Status == cloudformation.StackStatusUpdateRollbackComplete {
+		err = nil
+	}
+	if err != nil {
+		return explainFailure(err)
+	}
+
+	status, err := stacks.Status(svc, stackName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nStack %s is %s\n", stackName, stacks.FormatStackStatus(status))
+	if status != cloudformation.StackStatusUpdateRollbackComplete {
+		return fmt.Errorf("Stack rollback didn't complete")
+	}
+	return nil
+}
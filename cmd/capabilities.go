@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type capabilitiesFlags struct {
+	raw     []string
+	DenyIAM bool
+}
+
+func addCapabilitiesFlags(cmd *kingpin.CmdClause) *capabilitiesFlags {
+	f := &capabilitiesFlags{}
+
+	cmd.Flag("capabilities", "Capabilities to use instead of the ones the template requires, or to add to them if prefixed with +, comma separated or repeated").
+		StringsVar(&f.raw)
+
+	cmd.Flag("deny-iam", "Refuse to deploy templates that require IAM capabilities").
+		BoolVar(&f.DenyIAM)
+
+	return f
+}
+
+// Capabilities returns the requested capabilities, or nil to use the ones the template requires.
+// Capabilities to add to the required ones keep their + prefix
+func (f *capabilitiesFlags) Capabilities() []string {
+	if len(f.raw) == 0 {
+		return nil
+	}
+
+	caps := []string{}
+	for _, raw := range f.raw {
+		for _, c := range strings.Split(raw, ",") {
+			if c = strings.ToUpper(strings.TrimSpace(c)); c != "" {
+				caps = append(caps, c)
+			}
+		}
+	}
+	return caps
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureContinueUpdateRollback(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName string
+	var skipResources []string
+	var yes bool
+
+	cmd := app.Command("continue-update-rollback", "Continue rolling back a cloudformation stack in UPDATE_ROLLBACK_FAILED")
+	cmd.Alias("continue-rollback")
+
+	cmd.Flag("skip-resource", "The logical id of a resource to skip rolling back").
+		StringsVar(&skipResources)
+
+	cmd.Flag("yes", "Continue the rollback without asking for confirmation").
+		Short('y').
+		BoolVar(&yes)
+
+	events := addEventFlags(cmd)
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		svc := cloudformation.New(sess)
+
+		status, err := stacks.Status(svc, stackName)
+		if err != nil {
+			return err
+		}
+
+		if status != cloudformation.StackStatusUpdateRollbackFailed {
+			return fmt.Errorf("Stack %s is %s, rollback can only be continued from %s",
+				stackName, status, cloudformation.StackStatusUpdateRollbackFailed)
+		}
+
+		failures, err := stacks.RollbackFailures(svc, stackName)
+		if err != nil {
+			return err
+		}
+
+		failed := map[string]bool{}
+		if len(failures) > 0 {
+			fmt.Printf("Resources that failed during rollback:\n\n")
+			for _, event := range failures {
+				failed[*event.LogicalResourceId] = true
+				fmt.Printf("%s\n", stacks.FormatStackEvent(event))
+			}
+			fmt.Println()
+		}
+
+		for _, resource := range skipResources {
+			if !failed[resource] {
+				fmt.Printf("Warning: %s didn't fail during rollback\n", resource)
+			}
+		}
+
+		if !yes {
+			question := "Continue rollback?"
+			if len(skipResources) > 0 {
+				question = fmt.Sprintf("Continue rollback, skipping %v?", skipResources)
+			}
+			ok, err := confirm(os.Stdout, question)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+		}
+
+		t := time.Now()
+		if err = stacks.ContinueUpdateRollback(svc, stackName, skipResources); err != nil {
+			return err
+		}
+
+		// wait for the rollback to start, otherwise the previous failure ends the watch
+		if err = stacks.WaitForRollback(svc, stackName, t); err != nil {
+			return err
+		}
+
+		return watchRollback(svc, stackName, t, events)
+	})
+}
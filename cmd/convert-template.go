@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/lox/parfait/cmd/args"
+	"github.com/lox/parfait/templates"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureConvertTemplate(app *kingpin.Application, sess client.ConfigProvider) {
+	var to, output string
+
+	cmd := app.Command("convert-template", "Convert a cloudformation template between JSON and YAML")
+
+	tpl := args.TemplateSource(cmd.Flag("tpl", "Either a file path or url to a cloudformation template").
+		Short('t').
+		Required())
+
+	render := addRenderFlags(cmd)
+
+	cmd.Flag("to", "The format to convert the template to").
+		Required().
+		EnumVar(&to, string(templates.JSON), string(templates.YAML))
+
+	cmd.Flag("output-file", "A file to write the converted template to, defaults to stdout").
+		Short('f').
+		StringVar(&output)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		tpl, err := render.Apply(sess, tpl)
+		if err != nil {
+			return err
+		}
+
+		if render.RenderOnly {
+			fmt.Print(tpl.Body)
+			return nil
+		}
+
+		if tpl.Body == "" {
+			return fmt.Errorf("Templates in S3 can't be converted, download it first")
+		}
+
+		parsed, err := templates.Parse(tpl.Body)
+		if err != nil {
+			return fmt.Errorf("Failed to parse template: %v", err)
+		}
+
+		converted, err := parsed.Write(templates.Format(to))
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			_, err = os.Stdout.Write(converted)
+			return err
+		}
+
+		return ioutil.WriteFile(output, converted, 0644)
+	})
+}
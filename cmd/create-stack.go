@@ -2,7 +2,7 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
@@ -13,8 +13,10 @@ import (
 
 func ConfigureCreateStack(app *kingpin.Application, sess client.ConfigProvider) {
 	var stackName string
-	var params []string
+	var params, paramsFiles []string
+	var tags, tagsFiles []string
 	var disableRollback bool
+	var changeSet, yes bool
 
 	cmd := app.Command("create-stack", "Create a cloudformation stack")
 	cmd.Alias("create")
@@ -22,44 +24,100 @@ func ConfigureCreateStack(app *kingpin.Application, sess client.ConfigProvider)
 	tpl := args.TemplateSource(cmd.Flag("tpl", "Either a file path or url to a cloudformation template").
 		Short('t'))
 
+	caps := addCapabilitiesFlags(cmd)
+	upload := addUploadFlags(cmd)
+	render := addRenderFlags(cmd)
+	events := addEventFlags(cmd)
+	events.addFormatFlag(cmd)
+
+	cmd.Flag("params-file", "A JSON or YAML file of parameters, either in aws cli format or a map of keys to values").
+		StringsVar(&paramsFiles)
+
+	cmd.Flag("tag", "A tag to set on the stack in Key=Val form").
+		StringsVar(&tags)
+
+	cmd.Flag("tags-file", "A JSON or YAML file of tags, either in aws cli format or a map of keys to values").
+		StringsVar(&tagsFiles)
+
 	cmd.Flag("no-rollback", "Disable stack rollback on failure").
 		BoolVar(&disableRollback)
 
+	cmd.Flag("change-set", "Create the stack via a change set, showing the changes before executing them").
+		BoolVar(&changeSet)
+
+	cmd.Flag("yes", "Execute the change set without asking for confirmation").
+		Short('y').
+		BoolVar(&yes)
+
 	cmd.Arg("stack-name", "The name of the cloudformation stack").
 		StringVar(&stackName)
 
 	cmd.Arg("params", "Parameters to the stack in Key=Val form").
 		StringsVar(&params)
 
-	cmd.Action(func(c *kingpin.ParseContext) error {
-		params, err := parseStackParams(params)
+	cmd.Action(events.Action(&stackName, func(c *kingpin.ParseContext) error {
+		tpl, err := render.Apply(sess, tpl)
+		if err != nil {
+			return err
+		}
+
+		if render.RenderOnly {
+			fmt.Print(tpl.Body)
+			return nil
+		}
+
+		params, err := loadStackParams(paramsFiles, params)
+		if err != nil {
+			return err
+		}
+
+		if params, err = resolveStackParams(sess, params, events.Log()); err != nil {
+			return err
+		}
+
+		tags, err := loadStackTags(tagsFiles, tags)
+		if err != nil {
+			return err
+		}
+
+		tpl, err = upload.Prepare(sess, stackName, tpl)
 		if err != nil {
 			return err
 		}
 
 		ctx := stacks.CreateStackContext{
 			Params:          params,
-			Body:            tpl.String(),
+			Tags:            tags,
+			Body:            tpl.Body,
+			URL:             tpl.URL,
 			DisableRollback: disableRollback,
+			Capabilities:    caps.Capabilities(),
+			DenyIAM:         caps.DenyIAM,
 		}
 
 		cfn := cloudformation.New(sess)
 
+		if changeSet {
+			// a stack left in REVIEW_IN_PROGRESS by an earlier change set isn't deleted with this one
+			existing, err := stacks.FindByName(cfn, stackName)
+			if err != nil && !stacks.IsNotExistsErr(err) {
+				return err
+			}
+
+			cs, err := stacks.PlanCreate(cfn, stackName, ctx)
+			if err != nil {
+				return err
+			}
+			return applyChangeSet(cfn, stackName, cs, len(existing) == 0, yes, events)
+		}
+
 		if err = stacks.Create(cfn, stackName, ctx); err != nil {
 			return err
 		}
 
-		return stacks.Watch(cfn, stackName, func(event *cloudformation.StackEvent) {
-			fmt.Printf("%s\n", stacks.FormatStackEvent(event))
-		})
-	})
-}
+		printer := events.Printer(stackName, time.Time{})
+		defer printer.Stop()
 
-func parseStackParams(rawParams []string) (map[string]string, error) {
-	params := map[string]string{}
-	for _, arg := range rawParams {
-		parts := strings.SplitN(arg, "=", 2)
-		params[parts[0]] = parts[1]
-	}
-	return params, nil
+		return printer.Watch(cfn, printer.Print)
+	}))
 }
@@ -1,13 +1,11 @@
 package cmd
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/lox/parfait/stacks"
-	"github.com/lox/parfait/stacks/poller"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -20,11 +18,14 @@ func ConfigureDeleteStack(app *kingpin.Application, sess client.ConfigProvider)
 	cmd.Alias("remove")
 	cmd.Alias("rm")
 
+	events := addEventFlags(cmd)
+	events.addFormatFlag(cmd)
+
 	cmd.Arg("name", "The name of the cloudformation stack").
 		Required().
 		StringVar(&stackName)
 
-	cmd.Action(func(c *kingpin.ParseContext) error {
+	cmd.Action(events.Action(&stackName, func(c *kingpin.ParseContext) error {
 		cfn := cloudformation.New(sess)
 
 		t := time.Now()
@@ -32,10 +33,9 @@ func ConfigureDeleteStack(app *kingpin.Application, sess client.ConfigProvider)
 			return err
 		}
 
-		return poller.UntilDeleted(cfn, stackName, func(event *cloudformation.StackEvent) {
-			if event.Timestamp.After(t) {
-				fmt.Printf("%s\n", stacks.FormatStackEvent(event))
-			}
-		})
-	})
+		printer := events.Printer(stackName, t)
+		defer printer.Stop()
+
+		return printer.WatchDelete(cfn, printer.Print)
+	}))
 }
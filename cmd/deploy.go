@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/cmd/args"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureDeploy(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName string
+	var params, paramsFiles []string
+	var tags, tagsFiles, removeTags []string
+	var disableRollback, recreateFailed bool
+
+	cmd := app.Command("deploy", "Create a cloudformation stack, or update it if it already exists")
+
+	tpl := args.TemplateSource(cmd.Flag("tpl", "Either a file path or url to a cloudformation template").
+		Short('t'))
+
+	caps := addCapabilitiesFlags(cmd)
+	upload := addUploadFlags(cmd)
+	render := addRenderFlags(cmd)
+	events := addEventFlags(cmd)
+
+	cmd.Flag("params-file", "A JSON or YAML file of parameters, either in aws cli format or a map of keys to values").
+		StringsVar(&paramsFiles)
+
+	cmd.Flag("tag", "A tag to set on the stack in Key=Val form").
+		StringsVar(&tags)
+
+	cmd.Flag("tags-file", "A JSON or YAML file of tags, either in aws cli format or a map of keys to values").
+		StringsVar(&tagsFiles)
+
+	cmd.Flag("remove-tag", "The key of an existing tag to remove from the stack").
+		StringsVar(&removeTags)
+
+	cmd.Flag("no-rollback", "Disable stack rollback on failure when creating").
+		BoolVar(&disableRollback)
+
+	cmd.Flag("recreate-failed", "Delete and recreate stacks in ROLLBACK_COMPLETE, which can't be updated").
+		BoolVar(&recreateFailed)
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Arg("params", "Parameters to the stack in Key=Val form").
+		StringsVar(&params)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		tpl, err := render.Apply(sess, tpl)
+		if err != nil {
+			return err
+		}
+
+		if render.RenderOnly {
+			fmt.Print(tpl.Body)
+			return nil
+		}
+
+		params, err := loadStackParams(paramsFiles, params)
+		if err != nil {
+			return err
+		}
+
+		if params, err = resolveStackParams(sess, params, events.Log()); err != nil {
+			return err
+		}
+
+		tags, err := loadStackTags(tagsFiles, tags)
+		if err != nil {
+			return err
+		}
+
+		tpl, err = upload.Prepare(sess, stackName, tpl)
+		if err != nil {
+			return err
+		}
+
+		svc := cloudformation.New(sess)
+
+		existing, err := stacks.FindByName(svc, stackName)
+		if err != nil && !stacks.IsNotExistsErr(err) {
+			return err
+		}
+
+		create := len(existing) == 0
+
+		// a stack left in REVIEW_IN_PROGRESS by an unexecuted change set has never been created,
+		// and can only be created with another change set
+		review := !create && *existing[0].StackStatus == cloudformation.StackStatusReviewInProgress
+		if review && disableRollback {
+			return fmt.Errorf("Stack %s is in %s, which can't be created with --no-rollback",
+				stackName, *existing[0].StackStatus)
+		}
+
+		if !create && *existing[0].StackStatus == cloudformation.StackStatusRollbackComplete {
+			if !recreateFailed {
+				return fmt.Errorf("Stack %s is in %s and can't be updated, use --recreate-failed to delete and recreate it",
+					stackName, *existing[0].StackStatus)
+			}
+
+			fmt.Printf("Deleting stack %s in %s before recreating it\n", stackName, *existing[0].StackStatus)
+			if err = deleteAndWait(svc, stackName, events); err != nil {
+				return err
+			}
+			create = true
+		}
+
+		t := time.Now()
+
+		if review {
+			fmt.Printf("Creating stack %s in %s via a change set\n", stackName, *existing[0].StackStatus)
+			var cs *cloudformation.DescribeChangeSetOutput
+			cs, err = stacks.PlanCreate(svc, stackName, stacks.CreateStackContext{
+				Params:       params,
+				Tags:         tags,
+				Body:         tpl.Body,
+				URL:          tpl.URL,
+				Capabilities: caps.Capabilities(),
+				DenyIAM:      caps.DenyIAM,
+			})
+			if err == nil {
+				err = stacks.ExecuteChangeSet(svc, *cs.ChangeSetId)
+			}
+		} else if create {
+			fmt.Printf("Creating stack %s\n", stackName)
+			err = stacks.Create(svc, stackName, stacks.CreateStackContext{
+				Params:          params,
+				Tags:            tags,
+				Body:            tpl.Body,
+				URL:             tpl.URL,
+				DisableRollback: disableRollback,
+				Capabilities:    caps.Capabilities(),
+				DenyIAM:         caps.DenyIAM,
+			})
+		} else {
+			fmt.Printf("Updating stack %s\n", stackName)
+			err = stacks.Update(svc, stackName, stacks.UpdateStackContext{
+				Params:       params,
+				Tags:         tags,
+				RemoveTags:   removeTags,
+				Body:         tpl.Body,
+				URL:          tpl.URL,
+				Capabilities: caps.Capabilities(),
+				DenyIAM:      caps.DenyIAM,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		printer := events.Printer(stackName, t)
+		defer printer.Stop()
+
+		return printer.Watch(svc, printer.Print)
+	})
+}
+
+// deleteAndWait deletes a stack and polls until it's gone
+func deleteAndWait(svc *cloudformation.CloudFormation, stackName string, events *eventFlags) error {
+	t := time.Now()
+	if err := stacks.Delete(svc, stackName); err != nil {
+		return err
+	}
+
+	printer := events.Printer(stackName, t)
+	defer printer.Stop()
+
+	return printer.WatchDelete(svc, printer.Print)
+}
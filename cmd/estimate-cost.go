@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/cmd/args"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureEstimateCost(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName string
+	var params, paramsFiles []string
+
+	cmd := app.Command("estimate-cost", "Get a link to the AWS cost calculator for a template or existing stack")
+	cmd.Alias("cost")
+
+	tpl := args.TemplateSource(cmd.Flag("tpl", "Either a file path or url to a cloudformation template").
+		Short('t'))
+
+	render := addRenderFlags(cmd)
+	upload := addUploadFlags(cmd)
+
+	cmd.Flag("params-file", "A JSON or YAML file of parameters, either in aws cli format or a map of keys to values").
+		StringsVar(&paramsFiles)
+
+	cmd.Flag("stack", "The name of an existing stack to use the template and parameters of").
+		Short('s').
+		StringVar(&stackName)
+
+	cmd.Arg("params", "Parameters to the stack in Key=Val form").
+		StringsVar(&params)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		if tpl.IsEmpty() && stackName == "" {
+			return fmt.Errorf("Either a template or an existing stack name is required")
+		}
+
+		tpl, err := render.Apply(sess, tpl)
+		if err != nil {
+			return err
+		}
+
+		if render.RenderOnly {
+			fmt.Print(tpl.Body)
+			return nil
+		}
+
+		cliParams, err := loadStackParams(paramsFiles, params)
+		if err != nil {
+			return err
+		}
+
+		if cliParams, err = resolveStackParams(sess, cliParams, os.Stdout); err != nil {
+			return err
+		}
+
+		svc := cloudformation.New(sess)
+		estimateParams := map[string]string{}
+
+		if stackName != "" {
+			if tpl.IsEmpty() {
+				if tpl.Body, err = stacks.Template(svc, stackName); err != nil {
+					return err
+				}
+			}
+
+			if estimateParams, err = stacks.Parameters(svc, stackName); err != nil {
+				return err
+			}
+		}
+
+		for k, v := range cliParams {
+			estimateParams[k] = v
+		}
+
+		// the values of NoEcho parameters can't be read back, and there's no stack to reuse them from
+		hidden := []string{}
+		for k, v := range estimateParams {
+			if v == stacks.NoEchoValue {
+				hidden = append(hidden, k)
+			}
+		}
+		if len(hidden) > 0 {
+			sort.Strings(hidden)
+			return fmt.Errorf("The values of NoEcho parameters %s can't be read from stack %s, pass them as Key=Val",
+				strings.Join(hidden, ", "), stackName)
+		}
+
+		name := stackName
+		if name == "" {
+			name = "estimate-cost"
+		}
+		if tpl, err = upload.Prepare(sess, name, tpl); err != nil {
+			return err
+		}
+
+		url, err := stacks.EstimateCost(svc, tpl.Body, tpl.URL, estimateParams)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", url)
+		return nil
+	})
+}
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/fatih/color"
+	"github.com/lox/parfait/cmd/live"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	textEvents = "text"
+	jsonEvents = "json"
+)
+
+type eventFlags struct {
+	NoLive bool
+	Format string
+}
+
+func addEventFlags(cmd *kingpin.CmdClause) *eventFlags {
+	f := &eventFlags{Format: textEvents}
+
+	cmd.Flag("no-live", "Print a line per event, rather than a live table of resources when stdout is a terminal").
+		BoolVar(&f.NoLive)
+
+	return f
+}
+
+// addFormatFlag adds a flag for printing events as json lines for other tools to consume
+func (f *eventFlags) addFormatFlag(cmd *kingpin.CmdClause) {
+	cmd.Flag("format", "How to print stack events, either text or json, which prints an object per line").
+		Default(textEvents).
+		EnumVar(&f.Format, textEvents, jsonEvents)
+}
+
+// JSON returns whether events are printed as json lines
+func (f *eventFlags) JSON() bool {
+	return f.Format == jsonEvents
+}
+
+// Log returns where to print messages other than events, which is stderr when printing json so
+// that stdout only has json in it
+func (f *eventFlags) Log() io.Writer {
+	if f.JSON() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// Action wraps the action of a command that prints events. When printing json, colors are
+// turned off and errors that happen before a result is written are written as the result.
+func (f *eventFlags) Action(stackName *string, action kingpin.Action) kingpin.Action {
+	return func(c *kingpin.ParseContext) error {
+		if !f.JSON() {
+			return action(c)
+		}
+
+		color.NoColor = true
+
+		err := action(c)
+		if _, reported := err.(*reportedError); err == nil || reported {
+			return err
+		}
+		return writeResult(os.Stdout, nil, *stackName, err, false)
+	}
+}
+
+// Declined reports a change set that wasn't executed, which is printed as the result object
+// when printing json
+func (f *eventFlags) Declined(stackName string) error {
+	if !f.JSON() {
+		return nil
+	}
+	result := newResultObject(stackName, nil)
+	result.Outcome = "declined"
+	return newJSONEncoder(os.Stdout).Encode(result)
+}
+
+// Printer returns a printer for the events of a stack that happen after a time
+func (f *eventFlags) Printer(stackName string, after time.Time) *eventPrinter {
+	p := &eventPrinter{stackName: stackName, after: after, w: os.Stdout, log: f.Log()}
+	if f.JSON() {
+		p.enc = newJSONEncoder(p.w)
+	} else if !f.NoLive && live.IsTerminal() {
+		p.table = live.New(stackName)
+		p.table.Start()
+	}
+	return p
+}
+
+// eventPrinter prints stack events either as a live table of resources, a line per event or
+// as json lines
+type eventPrinter struct {
+	stackName string
+	after     time.Time
+	w         io.Writer
+	log       io.Writer
+	enc       *json.Encoder
+	table     *live.Table
+}
+
+// Print prints an event, it's passed to functions that watch stacks
+func (p *eventPrinter) Print(event *cloudformation.StackEvent) {
+	if !event.Timestamp.After(p.after) {
+		return
+	}
+	switch {
+	case p.enc != nil:
+		if err := p.enc.Encode(newEventObject(p.stackName, event)); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write event: %v\n", err)
+		}
+	case p.table != nil:
+		p.table.Event(event)
+	default:
+		fmt.Fprintf(p.w, "%s\n", stacks.FormatStackEvent(event))
+	}
+}
+
+// Watch polls the stack's events until a create or update finishes, passing them to f, which
+// wraps Print. Outputs are printed at the end, or the failure is explained.
+func (p *eventPrinter) Watch(svc *cloudformation.CloudFormation, f func(event *cloudformation.StackEvent)) error {
+	err := p.watch(svc, f)
+	if p.enc == nil {
+		explainFailure(err)
+	}
+	return err
+}
+
+// watch is Watch without explaining failures, for callers that print something first
+func (p *eventPrinter) watch(svc *cloudformation.CloudFormation, f func(event *cloudformation.StackEvent)) error {
+	if p.enc != nil {
+		return writeResult(p.w, svc, p.stackName, stacks.WaitForOperation(svc, p.stackName, f), false)
+	}
+	return stacks.Watch(svc, p.stackName, f)
+}
+
+// WatchDelete polls the stack's events until it's deleted, passing them to f, which wraps Print
+func (p *eventPrinter) WatchDelete(svc *cloudformation.CloudFormation, f func(event *cloudformation.StackEvent)) error {
+	err := stacks.WatchDelete(svc, p.stackName, f)
+	if p.enc != nil {
+		return writeResult(p.w, svc, p.stackName, err, true)
+	}
+	return explainFailure(err)
+}
+
+// Log returns where to print messages other than events
+func (p *eventPrinter) Log() io.Writer {
+	return p.log
+}
+
+// Pause stops redrawing a live table whilst something else is printed
+func (p *eventPrinter) Pause() {
+	if p.table != nil {
+		p.table.Pause()
+	}
+}
+
+// Resume starts redrawing a live table below whatever was printed whilst it was paused
+func (p *eventPrinter) Resume() {
+	if p.table != nil {
+		p.table.Resume()
+	}
+}
+
+// Stop stops redrawing a live table
+func (p *eventPrinter) Stop() {
+	if p.table != nil {
+		p.table.Stop()
+	}
+}
+
+// eventObject is a stack event as it's printed in json
+type eventObject struct {
+	Type                 string
+	Timestamp            time.Time
+	StackName            string
+	LogicalResourceId    string
+	PhysicalResourceId   string
+	ResourceType         string
+	ResourceStatus       string
+	ResourceStatusReason string
+	EventId              string
+}
+
+func newEventObject(stackName string, event *cloudformation.StackEvent) eventObject {
+	return eventObject{
+		Type:                 "event",
+		Timestamp:            aws.TimeValue(event.Timestamp).UTC(),
+		StackName:            stackName,
+		LogicalResourceId:    aws.StringValue(event.LogicalResourceId),
+		PhysicalResourceId:   aws.StringValue(event.PhysicalResourceId),
+		ResourceType:         aws.StringValue(event.ResourceType),
+		ResourceStatus:       aws.StringValue(event.ResourceStatus),
+		ResourceStatusReason: aws.StringValue(event.ResourceStatusReason),
+		EventId:              aws.StringValue(event.EventId),
+	}
+}
+
+// resultObject is the last object printed in json, with the outcome of an operation on a stack
+type resultObject struct {
+	Type        string
+	StackName   string
+	Outcome     string
+	StackStatus string `json:",omitempty"`
+	Error       string `json:",omitempty"`
+	ExitCode    int
+	Failures    []eventObject     `json:",omitempty"`
+	Outputs     map[string]string `json:",omitempty"`
+}
+
+// writeResult writes the result object for the outcome of an operation and returns its error,
+// marked as reported so that it isn't printed again. The status and outputs of a stack are looked
+// up when the operation succeeded.
+func writeResult(w io.Writer, svc *cloudformation.CloudFormation, stackName string, opErr error, deleted bool) error {
+	result := newResultObject(stackName, opErr)
+
+	if opErr == nil && deleted {
+		result.StackStatus = cloudformation.StackStatusDeleteComplete
+	} else if opErr == nil {
+		status, err := stacks.Status(svc, stackName)
+		if err != nil {
+			return err
+		}
+		outputs, err := stacks.Outputs(svc, stackName)
+		if err != nil {
+			return err
+		}
+		result.StackStatus, result.Outputs = status, outputs
+	}
+
+	if err := newJSONEncoder(w).Encode(result); err != nil {
+		return err
+	}
+	if opErr != nil {
+		return &reportedError{opErr}
+	}
+	return nil
+}
+
+func newResultObject(stackName string, err error) resultObject {
+	result := resultObject{
+		Type:      "result",
+		StackName: stackName,
+		Outcome:   "succeeded",
+		ExitCode:  ExitCode(err),
+	}
+
+	switch e := err.(type) {
+	case nil:
+	case *stacks.NoUpdatesError:
+		result.Outcome = "no-updates"
+		result.Error = e.Error()
+	case *stacks.OperationError:
+		result.Outcome = "failed"
+		result.StackStatus = e.Status
+		result.Error = e.Error()
+		for _, event := range e.Failures {
+			result.Failures = append(result.Failures, newEventObject(stackName, event))
+		}
+	default:
+		result.Outcome = "error"
+		result.Error = e.Error()
+	}
+
+	return result
+}
+
+// newJSONEncoder returns an encoder that writes an object per line, leaving characters like < and
+// > in reasons as they are
+func newJSONEncoder(w io.Writer) *json.Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc
+}
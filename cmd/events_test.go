@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+)
+
+func TestPrintingEventsAsJSON(t *testing.T) {
+	start := time.Date(2017, 3, 1, 10, 0, 0, 0, time.UTC)
+	buf := &bytes.Buffer{}
+
+	p := &eventPrinter{stackName: "app", after: start, w: buf}
+	p.enc = json.NewEncoder(buf)
+
+	for i, status := range []string{"UPDATE_COMPLETE", "UPDATE_IN_PROGRESS", "UPDATE_FAILED"} {
+		p.Print(&cloudformation.StackEvent{
+			EventId:              aws.String(status),
+			Timestamp:            aws.Time(start.Add(time.Duration(i) * time.Second)),
+			LogicalResourceId:    aws.String("Network/Vpc"),
+			PhysicalResourceId:   aws.String("vpc-1234"),
+			ResourceType:         aws.String("AWS::EC2::VPC"),
+			ResourceStatus:       aws.String(status),
+			ResourceStatusReason: aws.String("\x1b[31mnot colored\x1b[0m"),
+		})
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines for events after the start, got %d: %q", len(lines), lines)
+	}
+
+	var event map[string]string
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"Type":                 "event",
+		"Timestamp":            "2017-03-01T10:00:02Z",
+		"StackName":            "app",
+		"LogicalResourceId":    "Network/Vpc",
+		"PhysicalResourceId":   "vpc-1234",
+		"ResourceType":         "AWS::EC2::VPC",
+		"ResourceStatus":       "UPDATE_FAILED",
+		"ResourceStatusReason": "\x1b[31mnot colored\x1b[0m",
+		"EventId":              "UPDATE_FAILED",
+	}
+	for k, v := range expected {
+		if event[k] != v {
+			t.Errorf("Expected %s to be %q, got %q", k, v, event[k])
+		}
+	}
+
+	// escapes from a reason are encoded, so raw ANSI never reaches the output
+	if strings.Contains(buf.String(), "\x1b") {
+		t.Errorf("Expected no escape characters in %q", buf.String())
+	}
+}
+
+func TestResultObjects(t *testing.T) {
+	failure := &cloudformation.StackEvent{
+		Timestamp:         aws.Time(time.Now()),
+		LogicalResourceId: aws.String("Bucket"),
+		ResourceStatus:    aws.String("CREATE_FAILED"),
+	}
+
+	for _, tc := range []struct {
+		err      error
+		outcome  string
+		status   string
+		exitCode int
+		failures int
+	}{
+		{&stacks.NoUpdatesError{StackName: "app"}, "no-updates", "", 2, 0},
+		{&stacks.OperationError{StackName: "app", Status: "ROLLBACK_COMPLETE",
+			Failures: []*cloudformation.StackEvent{failure}}, "failed", "ROLLBACK_COMPLETE", 3, 1},
+		{&stacks.OperationError{StackName: "app", Status: "UPDATE_ROLLBACK_FAILED"}, "failed", "UPDATE_ROLLBACK_FAILED", 5, 0},
+	} {
+		result := newResultObject("app", tc.err)
+		if result.Type != "result" || result.StackName != "app" {
+			t.Errorf("Unexpected result %#v", result)
+		}
+		if result.Outcome != tc.outcome {
+			t.Errorf("Expected outcome %q for %v, got %q", tc.outcome, tc.err, result.Outcome)
+		}
+		if result.StackStatus != tc.status {
+			t.Errorf("Expected status %q for %v, got %q", tc.status, tc.err, result.StackStatus)
+		}
+		if result.ExitCode != tc.exitCode {
+			t.Errorf("Expected exit code %d for %v, got %d", tc.exitCode, tc.err, result.ExitCode)
+		}
+		if len(result.Failures) != tc.failures {
+			t.Errorf("Expected %d failures for %v, got %d", tc.failures, tc.err, len(result.Failures))
+		}
+		if result.Error != tc.err.Error() {
+			t.Errorf("Expected error %q, got %q", tc.err.Error(), result.Error)
+		}
+	}
+}
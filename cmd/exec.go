@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureExec(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName, prefix string
+	var command []string
+
+	cmd := app.Command("exec", "Run a command with the outputs of a cloudformation stack as environment variables")
+
+	cmd.Flag("prefix", "A prefix to add to the names of the environment variables").
+		StringVar(&prefix)
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Arg("command", "The command to run and its arguments, after a --").
+		Required().
+		StringsVar(&command)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		outputs, err := stacks.Outputs(cloudformation.New(sess), stackName)
+		if err != nil {
+			return err
+		}
+
+		env := os.Environ()
+		for k, v := range outputs {
+			env = append(env, prefix+k+"="+v)
+		}
+
+		proc := exec.Command(command[0], command[1:]...)
+		proc.Env = env
+		proc.Stdin = os.Stdin
+		proc.Stdout = os.Stdout
+		proc.Stderr = os.Stderr
+
+		// the command gets signals from the terminal directly, so parfait waits for it to exit
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt)
+		defer signal.Stop(signals)
+
+		if err = proc.Run(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() > 0 {
+				os.Exit(exitErr.ExitCode())
+			}
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+)
+
+// Exit codes of commands that change or watch stacks, which are documented in the README
+const (
+	exitSuccess        = 0
+	exitError          = 1
+	exitNoUpdates      = 2
+	exitCreateFailed   = 3
+	exitUpdateFailed   = 4
+	exitRollbackFailed = 5
+	exitDeleteFailed   = 6
+	exitInterrupted    = 130
+)
+
+// reportedError is an error that a command has already reported, like in a json result, so only
+// its exit code is needed
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string {
+	return e.err.Error()
+}
+
+// IsReported returns whether an error returned by a command has already been reported
+func IsReported(err error) bool {
+	_, ok := err.(*reportedError)
+	return ok
+}
+
+// ExitCode returns the process exit code for an error returned by a command
+func ExitCode(err error) int {
+	if reported, ok := err.(*reportedError); ok {
+		err = reported.err
+	}
+
+	switch e := err.(type) {
+	case nil:
+		return exitSuccess
+
+	case *stacks.NoUpdatesError:
+		return exitNoUpdates
+
+	case *stacks.OperationError:
+		switch e.Status {
+		case cloudformation.StackStatusCreateFailed,
+			cloudformation.StackStatusRollbackComplete:
+			return exitCreateFailed
+		case cloudformation.ResourceStatusUpdateFailed,
+			cloudformation.StackStatusUpdateRollbackComplete:
+			return exitUpdateFailed
+		case cloudformation.StackStatusRollbackFailed,
+			cloudformation.StackStatusUpdateRollbackFailed:
+			return exitRollbackFailed
+		case cloudformation.StackStatusDeleteFailed:
+			return exitDeleteFailed
+		}
+	}
+
+	return exitError
+}
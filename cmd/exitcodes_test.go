@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lox/parfait/stacks"
+)
+
+func TestExitCodes(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		code int
+	}{
+		{nil, 0},
+		{errors.New("Throttling"), 1},
+		{&stacks.NoUpdatesError{StackName: "app"}, 2},
+		{&stacks.OperationError{Status: "ROLLBACK_COMPLETE"}, 3},
+		{&stacks.OperationError{Status: "CREATE_FAILED"}, 3},
+		{&stacks.OperationError{Status: "UPDATE_FAILED"}, 4},
+		{&stacks.OperationError{Status: "UPDATE_ROLLBACK_COMPLETE"}, 4},
+		{&stacks.OperationError{Status: "ROLLBACK_FAILED"}, 5},
+		{&stacks.OperationError{Status: "UPDATE_ROLLBACK_FAILED"}, 5},
+		{&stacks.OperationError{Status: "DELETE_FAILED"}, 6},
+		{&reportedError{&stacks.OperationError{Status: "ROLLBACK_COMPLETE"}}, 3},
+	} {
+		if code := ExitCode(tc.err); code != tc.code {
+			t.Errorf("Expected exit code %d for %v, got %d", tc.code, tc.err, code)
+		}
+	}
+}
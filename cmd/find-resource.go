@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/cmd/output"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureFindResource(app *kingpin.Application, sess client.ConfigProvider) {
+	var physicalID string
+
+	cmd := app.Command("find-resource", "Find the cloudformation stack that owns a physical resource")
+	cmd.Alias("find")
+
+	cmd.Arg("physical-id", "The physical id of the resource, e.g an instance id or bucket name").
+		Required().
+		StringVar(&physicalID)
+
+	format := addOutputFlag(cmd)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		locations, err := stacks.FindResource(cloudformation.New(sess), physicalID)
+		if err != nil {
+			return err
+		}
+
+		if len(locations) == 0 {
+			return fmt.Errorf("No stack found that owns %s", physicalID)
+		}
+
+		table := output.NewTable(
+			output.Column{Header: "STACK", Key: "StackName", Width: 40},
+			output.Column{Header: "LOGICAL ID", Key: "LogicalResourceId", Width: 40},
+			output.Column{Header: "TYPE", Key: "ResourceType", Width: 40},
+			output.Column{Header: "PHYSICAL ID", Key: "PhysicalResourceId", Width: 60},
+		)
+		for _, l := range locations {
+			table.Add(l.StackName, l.LogicalID, l.Type, l.PhysicalID)
+		}
+		table.Sort(0, 1)
+
+		return table.Write(os.Stdout, *format)
+	})
+}
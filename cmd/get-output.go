@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureGetOutput(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName, key string
+
+	cmd := app.Command("get-output", "Print the value of a single output of a cloudformation stack")
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Arg("key", "The key of the output").
+		Required().
+		StringVar(&key)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		outputs, err := stacks.Outputs(cloudformation.New(sess), stackName)
+		if err != nil {
+			return err
+		}
+
+		value, ok := outputs[key]
+		if !ok {
+			return fmt.Errorf("Stack %s has no output %s", stackName, key)
+		}
+
+		fmt.Println(value)
+		return nil
+	})
+}
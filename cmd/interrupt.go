@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+)
+
+// interruptHandler catches Ctrl-C whilst a stack is being watched and offers to cancel the
+// update, stop watching or keep going. Events are held back whilst the question is asked.
+type interruptHandler struct {
+	svc       *cloudformation.CloudFormation
+	stackName string
+	printer   *eventPrinter
+	mu        sync.Mutex
+	signals   chan os.Signal
+	done      chan struct{}
+}
+
+func handleInterrupts(svc *cloudformation.CloudFormation, stackName string, printer *eventPrinter) *interruptHandler {
+	h := &interruptHandler{
+		svc:       svc,
+		stackName: stackName,
+		printer:   printer,
+		signals:   make(chan os.Signal, 1),
+		done:      make(chan struct{}),
+	}
+
+	signal.Notify(h.signals, os.Interrupt)
+
+	go func() {
+		for {
+			select {
+			case <-h.signals:
+				h.mu.Lock()
+				h.interrupted()
+				h.mu.Unlock()
+			case <-h.done:
+				return
+			}
+		}
+	}()
+
+	return h
+}
+
+func (h *interruptHandler) interrupted() {
+	// a live table would draw over the question
+	h.printer.Pause()
+	defer h.printer.Resume()
+
+	w := h.printer.Log()
+	fmt.Fprintln(w)
+
+	status, err := stacks.Status(h.svc, h.stackName)
+	if err != nil {
+		fmt.Fprintf(w, "Failed to get stack status: %v\n", err)
+		os.Exit(exitInterrupted)
+	}
+
+	options := []string{"stop", "keep-going"}
+	if status == cloudformation.StackStatusUpdateInProgress {
+		options = append([]string{"cancel"}, options...)
+	}
+
+	// a second Ctrl-C whilst asking exits immediately
+	signal.Stop(h.signals)
+	defer signal.Notify(h.signals, os.Interrupt)
+
+	choice, err := choose(w, fmt.Sprintf("Stack %s is %s, cancel the update, stop watching or keep going?",
+		h.stackName, stacks.FormatStackStatus(status)), options...)
+	if err != nil {
+		os.Exit(exitInterrupted)
+	}
+
+	switch choice {
+	case "cancel":
+		if err = stacks.CancelUpdate(h.svc, h.stackName); err != nil {
+			fmt.Fprintf(w, "Failed to cancel update: %v\n", err)
+		} else {
+			fmt.Fprintf(w, "Cancelling update, watching rollback\n")
+		}
+	case "stop":
+		fmt.Fprintf(w, "Stopped watching, stack %s is %s\n", h.stackName, stacks.FormatStackStatus(status))
+		os.Exit(exitInterrupted)
+	}
+}
+
+// Events wraps an event handler so that events are held back whilst a question is being asked
+func (h *interruptHandler) Events(f func(event *cloudformation.StackEvent)) func(event *cloudformation.StackEvent) {
+	return func(event *cloudformation.StackEvent) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		f(event)
+	}
+}
+
+// Stop stops catching Ctrl-C
+func (h *interruptHandler) Stop() {
+	signal.Stop(h.signals)
+	close(h.done)
+}
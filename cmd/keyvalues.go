@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// parseKeyValues parses Key=Val args, kind names what they are in errors
+func parseKeyValues(kind string, args []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid %s %q, expected Key=Val", strings.ToLower(kind), arg)
+		}
+		values[parts[0]] = parts[1]
+	}
+	return values, nil
+}
+
+// readKeyValuesFile reads a YAML or JSON file of either a list in the format that the aws cli
+// uses, which readList decodes into values, or a flat map of keys to values. Lists in a map are
+// joined with commas.
+func readKeyValuesFile(kind, path string, values map[string]string, readList func(node *yaml.Node, values map[string]string) error) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err = yaml.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("Failed to parse %ss file %s: %v", strings.ToLower(kind), path, err)
+	}
+
+	// empty files have no content
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	switch root := doc.Content[0]; root.Kind {
+	case yaml.SequenceNode:
+		err = readList(root, values)
+	case yaml.MappingNode:
+		err = readKeyValuesMap(kind, root, values)
+	default:
+		err = fmt.Errorf("Expected a list of %ss or a map of keys to values", strings.ToLower(kind))
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to read %ss file %s: %v", strings.ToLower(kind), path, err)
+	}
+
+	return nil
+}
+
+func readKeyValuesMap(kind string, node *yaml.Node, values map[string]string) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		switch value.Kind {
+		case yaml.ScalarNode:
+			if value.Tag == "!!null" {
+				values[key.Value] = ""
+			} else {
+				values[key.Value] = value.Value
+			}
+
+		// lists are joined, as CommaDelimitedList parameters expect
+		case yaml.SequenceNode:
+			items := []string{}
+			for _, item := range value.Content {
+				if item.Kind != yaml.ScalarNode {
+					return fmt.Errorf("%s %s must be a list of scalar values", kind, key.Value)
+				}
+				items = append(items, item.Value)
+			}
+			values[key.Value] = strings.Join(items, ",")
+
+		default:
+			return fmt.Errorf("%s %s must be a scalar value or a list", kind, key.Value)
+		}
+	}
+
+	return nil
+}
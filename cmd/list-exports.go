@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/cmd/output"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureListExports(app *kingpin.Application, sess client.ConfigProvider) {
+	var showConsumers bool
+
+	cmd := app.Command("list-exports", "List all cloudformation exports and the stacks that export them")
+	cmd.Alias("exports")
+
+	cmd.Flag("consumers", "Find the stacks that import each export by scanning their templates").
+		Short('c').
+		BoolVar(&showConsumers)
+
+	format := addOutputFlag(cmd)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		cfn := cloudformation.New(sess)
+
+		exports, err := stacks.Exports(cfn)
+		if err != nil {
+			return err
+		}
+
+		table := output.NewTable(
+			output.Column{Header: "NAME", Key: "Name", Width: 50},
+			output.Column{Header: "VALUE", Key: "Value", Width: 60},
+			output.Column{Header: "STACK", Key: "StackName", Width: 40},
+		)
+
+		if !showConsumers {
+			for _, e := range exports {
+				table.Add(e.Name, e.Value, e.StackName)
+			}
+			table.Sort(0)
+			return table.Write(os.Stdout, *format)
+		}
+
+		consumers, err := stacks.ExportConsumers(cfn, func(stackName string, err error) {
+			fmt.Fprintf(os.Stderr, "Warning: skipping stack %s: %v\n", stackName, err)
+		})
+		if err != nil {
+			return err
+		}
+
+		table.Columns = append(table.Columns, output.Column{Header: "IMPORTED BY", Key: "ImportedBy", Width: 60})
+		for _, e := range exports {
+			table.Add(e.Name, e.Value, e.StackName, strings.Join(consumers[e.Name], ","))
+		}
+		table.Sort(0)
+
+		return table.Write(os.Stdout, *format)
+	})
+}
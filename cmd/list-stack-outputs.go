@@ -1,24 +1,30 @@
 package cmd
 
 import (
-	"fmt"
+	"os"
 
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/cmd/output"
 	"github.com/lox/parfait/stacks"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 func ConfigureListStackOutputs(app *kingpin.Application, sess client.ConfigProvider) {
-	var stackName string
+	var stackName, envFormat string
 
-	cmd := app.Command("list-stack-outputs", "List all cloudformation stacks")
+	cmd := app.Command("list-stack-outputs", "List the outputs of a cloudformation stack")
 	cmd.Alias("outputs")
 
 	cmd.Arg("name", "The name of the cloudformation stack").
 		Required().
 		StringVar(&stackName)
 
+	format := addOutputFlag(cmd)
+
+	cmd.Flag("format", "Print outputs as variables for scripts, either dotenv, export or json").
+		EnumVar(&envFormat, output.EnvFormats...)
+
 	cmd.Action(func(c *kingpin.ParseContext) error {
 		cfn := cloudformation.New(sess)
 
@@ -27,10 +33,19 @@ func ConfigureListStackOutputs(app *kingpin.Application, sess client.ConfigProvi
 			return err
 		}
 
-		fmt.Printf("%-20s %-80s\n", "KEY", "VALUE")
+		if envFormat != "" {
+			return output.WriteEnv(os.Stdout, outputs, output.EnvFormat(envFormat))
+		}
+
+		table := output.NewTable(
+			output.Column{Header: "KEY", Key: "OutputKey", Width: 20},
+			output.Column{Header: "VALUE", Key: "OutputValue", Width: 80},
+		)
 		for k, v := range outputs {
-			fmt.Printf("%-20s %-80s\n", k, v)
+			table.Add(k, v)
 		}
-		return nil
+		table.Sort(0)
+
+		return table.Write(os.Stdout, *format)
 	})
 }
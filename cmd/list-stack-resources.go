@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/cmd/output"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureListStackResources(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName string
+	var types, statuses []string
+	var failed bool
+
+	cmd := app.Command("list-stack-resources", "List the resources in a cloudformation stack")
+	cmd.Alias("resources")
+
+	cmd.Flag("type", "Only show resources of this type, globs like AWS::EC2::* are supported").
+		StringsVar(&types)
+
+	cmd.Flag("status", "Only show resources with this status, globs like *_FAILED are supported").
+		StringsVar(&statuses)
+
+	cmd.Flag("failed", "Only show resources that have failed").
+		BoolVar(&failed)
+
+	format := addOutputFlag(cmd)
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		cfn := cloudformation.New(sess)
+
+		resources, err := stacks.Resources(cfn, stackName)
+		if err != nil {
+			return err
+		}
+
+		table := output.NewTable(
+			output.Column{Header: "LOGICAL ID", Key: "LogicalResourceId", Width: 40},
+			output.Column{Header: "TYPE", Key: "ResourceType", Width: 40},
+			output.Column{Header: "PHYSICAL ID", Key: "PhysicalResourceId", Width: 60},
+			output.Column{Header: "STATUS", Key: "ResourceStatus", Width: 30, Color: stacks.FormatStackStatus},
+			output.Column{Header: "REASON", Key: "ResourceStatusReason"},
+		)
+		for _, r := range filterResources(resources, types, statuses, failed) {
+			table.Add(
+				*r.LogicalResourceId,
+				*r.ResourceType,
+				aws.StringValue(r.PhysicalResourceId),
+				*r.ResourceStatus,
+				aws.StringValue(r.ResourceStatusReason),
+			)
+		}
+		table.Sort(0)
+
+		return table.Write(os.Stdout, *format)
+	})
+}
+
+// filterResources returns the resources that match any of the type and status patterns, and that
+// have failed if failed is set
+func filterResources(resources []*cloudformation.StackResourceSummary, types, statuses []string, failed bool) []*cloudformation.StackResourceSummary {
+	filtered := []*cloudformation.StackResourceSummary{}
+	for _, r := range resources {
+		if !matchesAny(types, *r.ResourceType) || !matchesAny(statuses, *r.ResourceStatus) {
+			continue
+		}
+		if failed && !strings.HasSuffix(*r.ResourceStatus, "_FAILED") {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+// matchesAny returns whether a value matches any of a list of glob patterns, an empty list
+// matches everything
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+func TestFilteringResources(t *testing.T) {
+	resource := func(id, resourceType, status string) *cloudformation.StackResourceSummary {
+		return &cloudformation.StackResourceSummary{
+			LogicalResourceId: aws.String(id),
+			ResourceType:      aws.String(resourceType),
+			ResourceStatus:    aws.String(status),
+		}
+	}
+	resources := []*cloudformation.StackResourceSummary{
+		resource("Vpc", "AWS::EC2::VPC", "CREATE_COMPLETE"),
+		resource("Subnet", "AWS::EC2::Subnet", "UPDATE_FAILED"),
+		resource("Queue", "AWS::SQS::Queue", "CREATE_FAILED"),
+		resource("Topic", "AWS::SNS::Topic", "UPDATE_IN_PROGRESS"),
+	}
+
+	for _, tc := range []struct {
+		name     string
+		types    []string
+		statuses []string
+		failed   bool
+		expected []string
+	}{
+		{"no filters", nil, nil, false, []string{"Vpc", "Subnet", "Queue", "Topic"}},
+		{"type glob", []string{"AWS::EC2::*"}, nil, false, []string{"Vpc", "Subnet"}},
+		{"several types", []string{"AWS::SQS::Queue", "AWS::SNS::Topic"}, nil, false, []string{"Queue", "Topic"}},
+		{"status", []string{}, []string{"UPDATE_*"}, false, []string{"Subnet", "Topic"}},
+		{"failed", nil, nil, true, []string{"Subnet", "Queue"}},
+		{"type and failed", []string{"AWS::EC2::*"}, nil, true, []string{"Subnet"}},
+		{"status and failed", nil, []string{"CREATE_*"}, true, []string{"Queue"}},
+	} {
+		ids := []string{}
+		for _, r := range filterResources(resources, tc.types, tc.statuses, tc.failed) {
+			ids = append(ids, *r.LogicalResourceId)
+		}
+		if !reflect.DeepEqual(ids, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, ids)
+		}
+	}
+}
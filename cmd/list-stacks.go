@@ -2,50 +2,128 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/cmd/output"
 	"github.com/lox/parfait/stacks"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
 func ConfigureListStacks(app *kingpin.Application, sess client.ConfigProvider) {
 	var showAll bool
+	var statuses, tags []string
+	var name, olderThan, newerThan, sortBy string
 
 	cmd := app.Command("list-stacks", "List all cloudformation stacks")
 	cmd.Alias("list")
 	cmd.Alias("ls")
 
-	cmd.Flag("all", "Show deleted stacks as well").
+	cmd.Flag("all", "Show stacks deleted in the last 90 days as well").
 		Short('a').
 		BoolVar(&showAll)
 
+	cmd.Flag("status", "Only show stacks with this status, globs like *_FAILED and DELETE_COMPLETE are supported").
+		StringsVar(&statuses)
+
+	cmd.Flag("name", "Only show stacks with names matching a glob, or a regex in slashes like /^app-/").
+		StringVar(&name)
+
+	cmd.Flag("tag", "Only show stacks with a tag in Key=Val form").
+		StringsVar(&tags)
+
+	cmd.Flag("older-than", "Only show stacks last changed before a duration ago like 12h or 7d, or a date").
+		StringVar(&olderThan)
+
+	cmd.Flag("newer-than", "Only show stacks last changed after a duration ago like 12h or 7d, or a date").
+		StringVar(&newerThan)
+
+	cmd.Flag("sort", "Sort stacks by name, status or updated, which shows the most recently changed first").
+		Default("name").
+		EnumVar(&sortBy, "name", "status", "updated")
+
+	format := addOutputFlag(cmd)
+
 	cmd.Action(func(c *kingpin.ParseContext) error {
-		var s []*cloudformation.Stack
+		filter := stacks.ListFilter{
+			Statuses:       statuses,
+			IncludeDeleted: showAll,
+			Name:           name,
+		}
+
 		var err error
+		if filter.Tags, err = loadStackTags(nil, tags); err != nil {
+			return err
+		}
 
-		if showAll {
-			s, err = stacks.FindAll(cloudformation.New(sess))
-		} else {
-			s, err = stacks.FindAllActive(cloudformation.New(sess))
+		now := time.Now()
+		if filter.OlderThan, err = parseTimeBound(olderThan, now); err != nil {
+			return err
+		}
+		if filter.NewerThan, err = parseTimeBound(newerThan, now); err != nil {
+			return err
 		}
+
+		summaries, err := stacks.List(cloudformation.New(sess), filter)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("%-60s %-40s %-20s\n", "NAME", "STATUS", "LAST UPDATED")
-		for _, stack := range s {
-			t := *stack.CreationTime
-			if stack.LastUpdatedTime != nil {
-				t = *stack.LastUpdatedTime
-			}
+		sortStackSummaries(summaries, sortBy)
 
-			fmt.Printf("%-60s %-40s %-20s\n",
-				*stack.StackName,
-				*stack.StackStatus,
-				t.String(),
-			)
+		table := output.NewTable(
+			output.Column{Header: "NAME", Key: "StackName", Width: 60},
+			output.Column{Header: "STATUS", Key: "StackStatus", Width: 40},
+			output.Column{Header: "LAST UPDATED", Key: "LastUpdatedTime", Width: 20},
+		)
+		for _, s := range summaries {
+			table.Add(*s.StackName, *s.StackStatus, stacks.LastChanged(s).UTC().Format(time.RFC3339))
 		}
-		return nil
+
+		return table.Write(os.Stdout, *format)
 	})
 }
+
+func sortStackSummaries(summaries []*cloudformation.StackSummary, sortBy string) {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		switch {
+		case sortBy == "status" && *a.StackStatus != *b.StackStatus:
+			return *a.StackStatus < *b.StackStatus
+		case sortBy == "updated" && !stacks.LastChanged(a).Equal(stacks.LastChanged(b)):
+			return stacks.LastChanged(a).After(stacks.LastChanged(b))
+		}
+		return *a.StackName < *b.StackName
+	})
+}
+
+// parseTimeBound parses either a duration before now like 30m, 12h or 7d, or a date or time in
+// RFC3339 format. An empty value returns a zero time.
+func parseTimeBound(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid time %q, expected a duration like 12h or 7d, or a date like 2006-01-02", value)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsingTimeBounds(t *testing.T) {
+	now := time.Date(2020, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for value, expected := range map[string]time.Time{
+		"":                     {},
+		"90m":                  time.Date(2020, 3, 10, 10, 30, 0, 0, time.UTC),
+		"7d":                   time.Date(2020, 3, 3, 12, 0, 0, 0, time.UTC),
+		"2020-01-02":           time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		"2020-01-02T03:04:05Z": time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	} {
+		actual, err := parseTimeBound(value, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !actual.Equal(expected) {
+			t.Errorf("Expected %q to be %v, got %v", value, expected, actual)
+		}
+	}
+
+	if _, err := parseTimeBound("last week", now); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+// +build !windows
+
+package live
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// terminalSize returns the width and height of a terminal, or zeros if it can't be found
+func terminalSize(fd uintptr) (width, height int) {
+	var ws struct {
+		Row, Col, X, Y uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, 0
+	}
+	return int(ws.Col), int(ws.Row)
+}
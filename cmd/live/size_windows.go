@@ -0,0 +1,6 @@
+package live
+
+// terminalSize returns zeros on windows, so the default size is used
+func terminalSize(fd uintptr) (width, height int) {
+	return 0, 0
+}
@@ -0,0 +1,351 @@
+package live
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+	"github.com/mattn/go-colorable"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	defaultWidth  = 80
+	defaultHeight = 25
+)
+
+// IsTerminal returns whether stdout is a terminal that a live table can be drawn on
+func IsTerminal() bool {
+	return isatty.IsTerminal(os.Stdout.Fd()) && os.Getenv("TERM") != "dumb"
+}
+
+type resource struct {
+	LogicalID string
+	Type      string
+	Status    string
+	Reason    string
+	Started   time.Time
+	Updated   time.Time
+}
+
+// Table is a table of the resources in a stack and their current status, which is redrawn in
+// place on a terminal as events arrive and every second to update elapsed times
+type Table struct {
+	w         io.Writer
+	stackName string
+	now       func() time.Time
+	size      func() (width, height int)
+
+	mu        sync.Mutex
+	status    string
+	started   time.Time
+	resources map[string]*resource
+	order     []string
+	lines     int
+	paused    bool
+	finished  bool
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// New returns a table that draws on stdout
+func New(stackName string) *Table {
+	return &Table{
+		w:         colorable.NewColorableStdout(),
+		stackName: stackName,
+		now:       time.Now,
+		size: func() (int, int) {
+			return terminalSize(os.Stdout.Fd())
+		},
+		started:   time.Now(),
+		resources: map[string]*resource{},
+		done:      make(chan struct{}),
+	}
+}
+
+// Start starts redrawing the table every second
+func (t *Table) Start() {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.mu.Lock()
+				if !t.paused && !t.finished {
+					t.draw()
+				}
+				t.mu.Unlock()
+			case <-t.done:
+				return
+			}
+		}
+	}()
+}
+
+// Event updates the table with a stack event. The table stops redrawing once the stack reaches
+// a terminal status, so that anything printed afterwards isn't drawn over.
+func (t *Table) Event(e *cloudformation.StackEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.finished {
+		return
+	}
+
+	status := aws.StringValue(e.ResourceStatus)
+	inProgress := strings.HasSuffix(status, "_IN_PROGRESS")
+
+	if aws.StringValue(e.LogicalResourceId) == aws.StringValue(e.StackName) {
+		t.status = status
+		switch status {
+		case cloudformation.StackStatusCreateInProgress,
+			cloudformation.StackStatusUpdateInProgress,
+			cloudformation.StackStatusDeleteInProgress:
+			t.started = *e.Timestamp
+		}
+		if !inProgress {
+			t.finished = true
+		}
+	} else {
+		id := aws.StringValue(e.LogicalResourceId)
+		r, ok := t.resources[id]
+		if !ok {
+			r = &resource{LogicalID: id}
+			t.resources[id] = r
+			t.order = append(t.order, id)
+		}
+		if inProgress && !strings.HasSuffix(r.Status, "_IN_PROGRESS") {
+			r.Started = *e.Timestamp
+		}
+		r.Type = aws.StringValue(e.ResourceType)
+		r.Status = status
+		r.Reason = aws.StringValue(e.ResourceStatusReason)
+		r.Updated = *e.Timestamp
+	}
+
+	if !t.paused {
+		t.draw()
+	}
+}
+
+// Pause stops drawing, for instance whilst a question is asked
+func (t *Table) Pause() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.paused = true
+}
+
+// Resume starts drawing again, below anything that was printed whilst paused
+func (t *Table) Resume() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.paused = false
+	t.lines = 0
+	if !t.finished {
+		t.draw()
+	}
+}
+
+// Stop draws the table for the last time and stops redrawing it
+func (t *Table) Stop() {
+	t.mu.Lock()
+	if !t.paused && !t.finished {
+		t.draw()
+	}
+	t.finished = true
+	select {
+	case <-t.done:
+	default:
+		close(t.done)
+	}
+	t.mu.Unlock()
+	t.wg.Wait()
+}
+
+// draw replaces the previously drawn table with the current one
+func (t *Table) draw() {
+	width, height := t.size()
+	if width <= 0 || height <= 0 {
+		width, height = defaultWidth, defaultHeight
+	}
+
+	lines := t.render(width, height)
+
+	buf := &strings.Builder{}
+	if t.lines > 0 {
+		fmt.Fprintf(buf, "\x1b[%dA", t.lines)
+	}
+	buf.WriteString("\r\x1b[J")
+	for _, line := range lines {
+		buf.WriteString(line + "\n")
+	}
+	io.WriteString(t.w, buf.String())
+
+	t.lines = len(lines)
+}
+
+// render returns the lines of the table, fitted to the size of the terminal
+func (t *Table) render(width, height int) []string {
+	now := t.now()
+
+	status := t.status
+	if status == "" {
+		status = "WAITING"
+	}
+	header := fmt.Sprintf("%s %s (%s)", t.stackName, status, formatDuration(now.Sub(t.started)))
+	lines := []string{
+		colorStatus(truncate(header, width-1), len(t.stackName)+1, len(status), status),
+		"",
+	}
+
+	// leave room for the column headers and the line the cursor ends up on
+	rows := t.visibleRows(height - len(lines) - 2)
+
+	headers := []string{"RESOURCE", "TYPE", "STATUS", "ELAPSED"}
+	widths := []int{len(headers[0]), len(headers[1]), len(headers[2]), len(headers[3])}
+	limits := []int{40, 36, 30, 8}
+
+	cells := [][]string{}
+	for _, r := range rows {
+		elapsed := ""
+		switch {
+		case r.Started.IsZero():
+		case strings.HasSuffix(r.Status, "_IN_PROGRESS"):
+			elapsed = formatDuration(now.Sub(r.Started))
+		default:
+			elapsed = formatDuration(r.Updated.Sub(r.Started))
+		}
+		row := []string{truncate(r.LogicalID, limits[0]), truncate(r.Type, limits[1]), truncate(r.Status, limits[2]), elapsed}
+		for i, v := range row {
+			if len(v) > widths[i] {
+				widths[i] = len(v)
+			}
+		}
+		cells = append(cells, row)
+	}
+
+	reasonWidth := width - 1
+	for _, w := range widths {
+		reasonWidth -= w + 1
+	}
+
+	columns := ""
+	for i, h := range headers {
+		columns += pad(h, widths[i]) + " "
+	}
+	lines = append(lines, truncate(columns+"REASON", width-1))
+
+	// rows are cut to the width before the status is colored, so that the color codes aren't
+	// counted and rows never wrap, which would throw out redrawing
+	for i, row := range cells {
+		line := ""
+		statusStart := 0
+		for j, v := range row {
+			if j == 2 {
+				statusStart = len(line)
+			}
+			line += pad(v, widths[j]) + " "
+		}
+		if reasonWidth > 0 {
+			line += truncate(strings.Replace(rows[i].Reason, "\n", " ", -1), reasonWidth)
+		}
+		line = truncate(strings.TrimRight(line, " "), width-1)
+		lines = append(lines, colorStatus(line, statusStart, len(row[2]), rows[i].Status))
+	}
+
+	if hidden := len(t.order) - len(rows); hidden > 0 {
+		lines = append(lines, fmt.Sprintf("... and %d more resources", hidden))
+	}
+
+	return lines
+}
+
+// visibleRows returns the resources to show in the space available. If there are too many, the
+// resources that are in progress or failed are shown first, followed by the most recently updated.
+func (t *Table) visibleRows(max int) []*resource {
+	if max < 1 {
+		max = 1
+	}
+	if len(t.order) <= max {
+		rows := []*resource{}
+		for _, id := range t.order {
+			rows = append(rows, t.resources[id])
+		}
+		return rows
+	}
+
+	// leave room for the line that says how many are hidden
+	max--
+
+	shown := map[string]bool{}
+	for _, id := range t.order {
+		if len(shown) < max && !strings.HasSuffix(t.resources[id].Status, "_COMPLETE") {
+			shown[id] = true
+		}
+	}
+	for i := len(t.order) - 1; i >= 0 && len(shown) < max; i-- {
+		shown[t.order[i]] = true
+	}
+
+	rows := []*resource{}
+	for _, id := range t.order {
+		if shown[id] {
+			rows = append(rows, t.resources[id])
+		}
+	}
+	return rows
+}
+
+// colorStatus colors the part of a line of plain text from start to start+length, which shows
+// a status, with the color of the status
+func colorStatus(line string, start, length int, status string) string {
+	if start >= len(line) {
+		return line
+	}
+	end := start + length
+	if end > len(line) {
+		end = len(line)
+	}
+	colored := strings.Replace(stacks.FormatStackStatus(status), status, line[start:end], 1)
+	return line[:start] + colored + line[end:]
+}
+
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func pad(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
+
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		return s[:width]
+	}
+	return s[:width-3] + "..."
+}
@@ -0,0 +1,110 @@
+package live
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/fatih/color"
+)
+
+func testTable(buf *bytes.Buffer, start time.Time, height int) *Table {
+	return &Table{
+		w:         buf,
+		stackName: "app",
+		now:       func() time.Time { return start.Add(90 * time.Second) },
+		size:      func() (int, int) { return 100, height },
+		started:   start,
+		resources: map[string]*resource{},
+		done:      make(chan struct{}),
+	}
+}
+
+func event(logicalID, resourceType, status, reason string, at time.Time) *cloudformation.StackEvent {
+	return &cloudformation.StackEvent{
+		StackName:            aws.String("app"),
+		LogicalResourceId:    aws.String(logicalID),
+		ResourceType:         aws.String(resourceType),
+		ResourceStatus:       aws.String(status),
+		ResourceStatusReason: aws.String(reason),
+		Timestamp:            aws.Time(at),
+	}
+}
+
+func TestDrawingTable(t *testing.T) {
+	color.NoColor = true
+	start := time.Now()
+	buf := &bytes.Buffer{}
+	table := testTable(buf, start, 25)
+
+	table.Event(event("app", "AWS::CloudFormation::Stack", "UPDATE_IN_PROGRESS", "User Initiated", start))
+	table.Event(event("Bucket", "AWS::S3::Bucket", "UPDATE_IN_PROGRESS", "", start.Add(5*time.Second)))
+	table.Event(event("Queue", "AWS::SQS::Queue", "UPDATE_IN_PROGRESS", "", start.Add(6*time.Second)))
+	buf.Reset()
+	table.Event(event("Queue", "AWS::SQS::Queue", "UPDATE_COMPLETE", "", start.Add(16*time.Second)))
+
+	expected := "\x1b[5A\r\x1b[J" +
+		"app UPDATE_IN_PROGRESS (1m30s)\n" +
+		"\n" +
+		"RESOURCE TYPE            STATUS             ELAPSED REASON\n" +
+		"Bucket   AWS::S3::Bucket UPDATE_IN_PROGRESS 1m25s\n" +
+		"Queue    AWS::SQS::Queue UPDATE_COMPLETE    10s\n"
+
+	if buf.String() != expected {
+		t.Fatalf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestDrawingTableWithTooManyResources(t *testing.T) {
+	color.NoColor = true
+	start := time.Now()
+	table := testTable(&bytes.Buffer{}, start, 10)
+
+	for i := 0; i < 20; i++ {
+		status := "CREATE_COMPLETE"
+		if i == 3 {
+			status = "CREATE_FAILED"
+		}
+		table.Event(event(fmt.Sprintf("Topic%02d", i), "AWS::SNS::Topic", status, "", start.Add(time.Duration(i)*time.Second)))
+	}
+
+	lines := table.render(100, 10)
+	if len(lines) > 10 {
+		t.Fatalf("Expected at most 10 lines, got %d", len(lines))
+	}
+
+	rendered := strings.Join(lines, "\n")
+	for _, expected := range []string{"Topic03", "Topic19", "... and 15 more resources"} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("Expected %q in %s", expected, rendered)
+		}
+	}
+}
+
+func TestDrawingTableNarrowerThanRows(t *testing.T) {
+	color.NoColor = false
+	defer func() { color.NoColor = true }()
+
+	start := time.Now()
+	table := testTable(&bytes.Buffer{}, start, 10)
+	table.Event(event("ApplicationLoadBalancerListener", "AWS::ElasticLoadBalancingV2::ListenerRule",
+		"UPDATE_ROLLBACK_IN_PROGRESS", "", start))
+	table.Event(event("Bucket", "AWS::S3::Bucket", "UPDATE_COMPLETE", "Resource updated", start))
+
+	ansi := regexp.MustCompile("\x1b\\[[0-9;]*m")
+	for _, line := range table.render(80, 10) {
+		if visible := ansi.ReplaceAllString(line, ""); len(visible) > 79 {
+			t.Errorf("Expected at most 79 columns, got %d: %q", len(visible), visible)
+		}
+	}
+
+	rendered := strings.Join(table.render(80, 10), "\n")
+	if !strings.Contains(rendered, "\x1b[") {
+		t.Errorf("Expected statuses to still be colored in %q", rendered)
+	}
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/lox/parfait/cmd/output"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// addOutputFlag adds a flag for the format that a read command prints its results in
+func addOutputFlag(cmd *kingpin.CmdClause) *output.Format {
+	var format string
+
+	cmd.Flag("output", "The format to print results in, either table, json, yaml or tsv").
+		Short('o').
+		Default(string(output.TableFormat)).
+		EnumVar(&format, output.Formats...)
+
+	return (*output.Format)(&format)
+}
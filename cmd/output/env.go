@@ -0,0 +1,73 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type EnvFormat string
+
+const (
+	DotenvFormat  EnvFormat = "dotenv"
+	ExportFormat  EnvFormat = "export"
+	EnvJSONFormat EnvFormat = "json"
+)
+
+// EnvFormats are the names of the supported env formats, for use in flags
+var EnvFormats = []string{
+	string(DotenvFormat),
+	string(ExportFormat),
+	string(EnvJSONFormat),
+}
+
+// WriteEnv writes variables sorted by name, either as a .env file, as shell export statements or
+// as a JSON object
+func WriteEnv(w io.Writer, vars map[string]string, format EnvFormat) error {
+	if format == EnvJSONFormat {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(vars)
+	}
+
+	names := []string{}
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var line string
+		switch format {
+		case DotenvFormat:
+			line = fmt.Sprintf("%s=%s\n", name, dotenvQuote(vars[name]))
+		case ExportFormat:
+			line = fmt.Sprintf("export %s=%s\n", name, shellQuote(vars[name]))
+		default:
+			return fmt.Errorf("Unknown env format %q", format)
+		}
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var dotenvEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`)
+
+// dotenvQuote double quotes values that contain anything other than safe characters
+func dotenvQuote(v string) string {
+	if v != "" && strings.Trim(v, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.:/@,+=") == "" {
+		return v
+	}
+	return `"` + dotenvEscaper.Replace(v) + `"`
+}
+
+// shellQuote single quotes a value for a POSIX shell
+func shellQuote(v string) string {
+	return "'" + strings.Replace(v, "'", `'\''`, -1) + "'"
+}
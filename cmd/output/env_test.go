@@ -0,0 +1,33 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWritingEnvFormats(t *testing.T) {
+	vars := map[string]string{
+		"VpcId":   "vpc-123",
+		"Name":    `it's "$HOME"`,
+		"Subnets": "a,b",
+	}
+
+	for format, expected := range map[EnvFormat]string{
+		DotenvFormat: "Name=\"it's \\\"\\$HOME\\\"\"\nSubnets=a,b\nVpcId=vpc-123\n",
+		ExportFormat: "export Name='it'\\''s \"$HOME\"'\nexport Subnets='a,b'\nexport VpcId='vpc-123'\n",
+		EnvJSONFormat: `{
+  "Name": "it's \"$HOME\"",
+  "Subnets": "a,b",
+  "VpcId": "vpc-123"
+}
+`,
+	} {
+		buf := &bytes.Buffer{}
+		if err := WriteEnv(buf, vars, format); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expected {
+			t.Errorf("Expected %s output %q, got %q", format, expected, buf.String())
+		}
+	}
+}
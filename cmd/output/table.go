@@ -0,0 +1,199 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	TableFormat Format = "table"
+	JSONFormat  Format = "json"
+	YAMLFormat  Format = "yaml"
+	TSVFormat   Format = "tsv"
+)
+
+// Formats are the names of the supported formats, for use in flags
+var Formats = []string{
+	string(TableFormat),
+	string(JSONFormat),
+	string(YAMLFormat),
+	string(TSVFormat),
+}
+
+// Column is a column in a table. The header is shown in table and tsv output and the key is used
+// for the fields of json and yaml output. Color optionally colors values in table output.
+type Column struct {
+	Header string
+	Key    string
+	Width  int
+	Color  func(string) string
+}
+
+// Table is a list of rows that can be written in any of the formats
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+}
+
+// NewTable returns an empty table with the given columns
+func NewTable(columns ...Column) *Table {
+	return &Table{Columns: columns}
+}
+
+// Add adds a row, with a value for each column
+func (t *Table) Add(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// Sort sorts the rows by the values in the given columns in order
+func (t *Table) Sort(columns ...int) {
+	sort.SliceStable(t.Rows, func(i, j int) bool {
+		for _, c := range columns {
+			if t.Rows[i][c] != t.Rows[j][c] {
+				return t.Rows[i][c] < t.Rows[j][c]
+			}
+		}
+		return false
+	})
+}
+
+// Write writes the table in a format
+func (t *Table) Write(w io.Writer, format Format) error {
+	switch format {
+	case TableFormat:
+		return t.writeTable(w)
+	case JSONFormat:
+		return t.writeJSON(w)
+	case YAMLFormat:
+		return t.writeYAML(w)
+	case TSVFormat:
+		return t.writeTSV(w)
+	}
+	return fmt.Errorf("Unknown output format %q", format)
+}
+
+func (t *Table) writeTable(w io.Writer) error {
+	line := func(values []string, color bool) string {
+		cells := make([]string, len(values))
+		for i, v := range values {
+			cells[i] = v
+			if color && t.Columns[i].Color != nil {
+				cells[i] = t.Columns[i].Color(v)
+			}
+			// pad by the length of the value, as color codes take no space
+			if n := utf8.RuneCountInString(v); n < t.Columns[i].Width {
+				cells[i] += strings.Repeat(" ", t.Columns[i].Width-n)
+			}
+		}
+		return strings.TrimRight(strings.Join(cells, " "), " ") + "\n"
+	}
+
+	headers := []string{}
+	for _, c := range t.Columns {
+		headers = append(headers, c.Header)
+	}
+
+	if _, err := io.WriteString(w, line(headers, false)); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		if _, err := io.WriteString(w, line(row, true)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSON writes the rows as a list of objects, with fields in the order of the columns
+func (t *Table) writeJSON(w io.Writer) error {
+	buf := &bytes.Buffer{}
+	buf.WriteString("[")
+
+	for i, row := range t.Rows {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  {")
+		for j, v := range row {
+			if j > 0 {
+				buf.WriteString(",")
+			}
+			key, _ := json.Marshal(t.Columns[j].Key)
+			value, _ := json.Marshal(v)
+			fmt.Fprintf(buf, "\n    %s: %s", key, value)
+		}
+		buf.WriteString("\n  }")
+	}
+
+	if len(t.Rows) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeYAML writes the rows as a list of maps, with keys in the order of the columns
+func (t *Table) writeYAML(w io.Writer) error {
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+
+	for _, row := range t.Rows {
+		item := &yaml.Node{Kind: yaml.MappingNode}
+		for j, v := range row {
+			item.Content = append(item.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t.Columns[j].Key},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v},
+			)
+		}
+		list.Content = append(list.Content, item)
+	}
+
+	if len(list.Content) == 0 {
+		list.Style = yaml.FlowStyle
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(list); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+var tsvEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r")
+
+// writeTSV writes a header line followed by a line per row, escaping tabs and newlines in values
+func (t *Table) writeTSV(w io.Writer) error {
+	line := func(values []string) string {
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = tsvEscaper.Replace(v)
+		}
+		return strings.Join(escaped, "\t") + "\n"
+	}
+
+	headers := []string{}
+	for _, c := range t.Columns {
+		headers = append(headers, c.Key)
+	}
+
+	if _, err := io.WriteString(w, line(headers)); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		if _, err := io.WriteString(w, line(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
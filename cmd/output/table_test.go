@@ -0,0 +1,84 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testTable() *Table {
+	t := NewTable(
+		Column{Header: "KEY", Key: "Key", Width: 6},
+		Column{Header: "VALUE", Key: "Value", Width: 10},
+	)
+	t.Add("Zone", "a\tb")
+	t.Add("Arn", `arn:"x"`)
+	t.Sort(0)
+	return t
+}
+
+func TestWritingTableFormats(t *testing.T) {
+	for format, expected := range map[Format]string{
+		TableFormat: "KEY    VALUE\nArn    arn:\"x\"\nZone   a\tb\n",
+		TSVFormat:   "Key\tValue\nArn\tarn:\"x\"\nZone\ta\\tb\n",
+		JSONFormat: `[
+  {
+    "Key": "Arn",
+    "Value": "arn:\"x\""
+  },
+  {
+    "Key": "Zone",
+    "Value": "a\tb"
+  }
+]
+`,
+		YAMLFormat: `- Key: Arn
+  Value: arn:"x"
+- Key: Zone
+  Value: "a\tb"
+`,
+	} {
+		buf := &bytes.Buffer{}
+		if err := testTable().Write(buf, format); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expected {
+			t.Errorf("Expected %s output %q, got %q", format, expected, buf.String())
+		}
+	}
+}
+
+func TestWritingEmptyTable(t *testing.T) {
+	for format, expected := range map[Format]string{
+		JSONFormat: "[]\n",
+		YAMLFormat: "[]\n",
+	} {
+		buf := &bytes.Buffer{}
+		if err := NewTable(Column{Header: "KEY", Key: "Key"}).Write(buf, format); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expected {
+			t.Errorf("Expected %s output %q, got %q", format, expected, buf.String())
+		}
+	}
+}
+
+func TestColoringTableColumns(t *testing.T) {
+	table := NewTable(
+		Column{Header: "STATUS", Key: "Status", Width: 8, Color: func(v string) string { return "<" + v + ">" }},
+		Column{Header: "KEY", Key: "Key"},
+	)
+	table.Add("OK", "a")
+
+	for format, expected := range map[Format]string{
+		TableFormat: "STATUS   KEY\n<OK>       a\n",
+		TSVFormat:   "Status\tKey\nOK\ta\n",
+	} {
+		buf := &bytes.Buffer{}
+		if err := table.Write(buf, format); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expected {
+			t.Errorf("Expected %s output %q, got %q", format, expected, buf.String())
+		}
+	}
+}
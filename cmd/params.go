@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// loadStackParams reads parameters from params files in order followed by Key=Val args,
+// with later values overriding earlier ones
+func loadStackParams(files []string, rawParams []string) (map[string]string, error) {
+	params := map[string]string{}
+
+	for _, file := range files {
+		if err := readKeyValuesFile("Parameter", file, params, readParamsList); err != nil {
+			return nil, err
+		}
+	}
+
+	cliParams, err := parseKeyValues("Parameter", rawParams)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range cliParams {
+		params[k] = v
+	}
+
+	return params, nil
+}
+
+type cliParameter struct {
+	ParameterKey     string  `yaml:"ParameterKey"`
+	ParameterValue   *string `yaml:"ParameterValue"`
+	UsePreviousValue bool    `yaml:"UsePreviousValue"`
+}
+
+// readParamsList reads the JSON parameter list format that the aws cli uses. Parameters with
+// UsePreviousValue are removed from params so that updates fall back to the previous value.
+func readParamsList(node *yaml.Node, params map[string]string) error {
+	var list []cliParameter
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+
+	for idx, p := range list {
+		switch {
+		case p.ParameterKey == "":
+			return fmt.Errorf("Parameter %d has no ParameterKey", idx)
+		case p.UsePreviousValue:
+			delete(params, p.ParameterKey)
+		case p.ParameterValue == nil:
+			return fmt.Errorf("Parameter %s has no ParameterValue", p.ParameterKey)
+		default:
+			params[p.ParameterKey] = *p.ParameterValue
+		}
+	}
+
+	return nil
+}
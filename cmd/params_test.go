@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadingStackParamsFromFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parfait-params")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cliFormat := writeTempFile(t, dir, "params.json", `[
+		{"ParameterKey": "VpcId", "ParameterValue": "vpc-1234"},
+		{"ParameterKey": "InstanceCount", "ParameterValue": "2"},
+		{"ParameterKey": "KeyName", "ParameterValue": "llamas"}
+	]`)
+
+	flatFormat := writeTempFile(t, dir, "params.yml", `
+InstanceCount: 4
+Subnets:
+  - subnet-1
+  - subnet-2
+KeyName:
+`)
+
+	params, err := loadStackParams([]string{cliFormat, flatFormat}, []string{"KeyName=alpacas"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"VpcId":         "vpc-1234",
+		"InstanceCount": "4",
+		"Subnets":       "subnet-1,subnet-2",
+		"KeyName":       "alpacas",
+	}
+
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("Expected %#v, got %#v", expected, params)
+	}
+}
+
+func TestUsePreviousValueRemovesEarlierParams(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parfait-params")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	first := writeTempFile(t, dir, "first.json", `{"VpcId": "vpc-1234"}`)
+	second := writeTempFile(t, dir, "second.json", `[
+		{"ParameterKey": "VpcId", "UsePreviousValue": true}
+	]`)
+
+	params, err := loadStackParams([]string{first, second}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := params["VpcId"]; ok {
+		t.Fatalf("Expected VpcId to be removed, got %#v", params)
+	}
+}
+
+func TestParsingMalformedStackParams(t *testing.T) {
+	for _, arg := range []string{"VpcId", "=vpc-1234"} {
+		if _, err := parseKeyValues("Parameter", []string{arg}); err == nil {
+			t.Errorf("Expected an error for %q", arg)
+		}
+	}
+}
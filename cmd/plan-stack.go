@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/cmd/args"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigurePlanStack(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName string
+	var params, paramsFiles []string
+	var tags, tagsFiles, removeTags []string
+	var yes bool
+
+	cmd := app.Command("plan-stack", "Show the changes to a cloudformation stack via a change set and then apply them")
+	cmd.Alias("plan")
+
+	tpl := args.TemplateSource(cmd.Flag("tpl", "Either a file path or url to a cloudformation template").
+		Short('t'))
+
+	caps := addCapabilitiesFlags(cmd)
+	upload := addUploadFlags(cmd)
+	render := addRenderFlags(cmd)
+	events := addEventFlags(cmd)
+
+	cmd.Flag("params-file", "A JSON or YAML file of parameters, either in aws cli format or a map of keys to values").
+		StringsVar(&paramsFiles)
+
+	cmd.Flag("tag", "A tag to set on the stack in Key=Val form").
+		StringsVar(&tags)
+
+	cmd.Flag("tags-file", "A JSON or YAML file of tags, either in aws cli format or a map of keys to values").
+		StringsVar(&tagsFiles)
+
+	cmd.Flag("remove-tag", "The key of an existing tag to remove from the stack").
+		StringsVar(&removeTags)
+
+	cmd.Flag("yes", "Execute the change set without asking for confirmation").
+		Short('y').
+		BoolVar(&yes)
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Arg("params", "Parameters to the stack in Key=Val form").
+		StringsVar(&params)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		tpl, err := render.Apply(sess, tpl)
+		if err != nil {
+			return err
+		}
+
+		if render.RenderOnly {
+			fmt.Print(tpl.Body)
+			return nil
+		}
+
+		params, err := loadStackParams(paramsFiles, params)
+		if err != nil {
+			return err
+		}
+
+		if params, err = resolveStackParams(sess, params, events.Log()); err != nil {
+			return err
+		}
+
+		tags, err := loadStackTags(tagsFiles, tags)
+		if err != nil {
+			return err
+		}
+
+		tpl, err = upload.Prepare(sess, stackName, tpl)
+		if err != nil {
+			return err
+		}
+
+		svc := cloudformation.New(sess)
+
+		existing, err := stacks.FindByName(svc, stackName)
+		if err != nil && !stacks.IsNotExistsErr(err) {
+			return err
+		}
+
+		var cs *cloudformation.DescribeChangeSetOutput
+
+		if len(existing) == 0 || *existing[0].StackStatus == cloudformation.StackStatusReviewInProgress {
+			cs, err = stacks.PlanCreate(svc, stackName, stacks.CreateStackContext{
+				Params:       params,
+				Tags:         tags,
+				Body:         tpl.Body,
+				URL:          tpl.URL,
+				Capabilities: caps.Capabilities(),
+				DenyIAM:      caps.DenyIAM,
+			})
+		} else {
+			cs, err = stacks.PlanUpdate(svc, stackName, stacks.UpdateStackContext{
+				Params:       params,
+				Tags:         tags,
+				RemoveTags:   removeTags,
+				Body:         tpl.Body,
+				URL:          tpl.URL,
+				Capabilities: caps.Capabilities(),
+				DenyIAM:      caps.DenyIAM,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		return applyChangeSet(svc, stackName, cs, len(existing) == 0, yes, events)
+	})
+}
+
+// applyChangeSet shows the changes in a change set, asks for confirmation and then executes it
+// and watches the stack. Change sets that aren't executed are deleted, along with the empty stack
+// if the change set created it.
+func applyChangeSet(svc *cloudformation.CloudFormation, stackName string, cs *cloudformation.DescribeChangeSetOutput, newStack bool, yes bool, events *eventFlags) error {
+	if stacks.IsEmptyChangeSet(cs) {
+		if err := stacks.DeleteChangeSet(svc, *cs.ChangeSetId); err != nil {
+			return err
+		}
+		return &stacks.NoUpdatesError{StackName: stackName}
+	}
+
+	// the changes are printed with the other messages, keeping json events on stdout
+	w := events.Log()
+	fmt.Fprintf(w, "Change set %s for %s:\n\n", *cs.ChangeSetName, stackName)
+	for _, change := range cs.Changes {
+		fmt.Fprintf(w, "%s\n", stacks.FormatChange(change))
+	}
+	fmt.Fprintln(w)
+
+	discard := func() error {
+		fmt.Fprintf(w, "Discarding change set %s\n", *cs.ChangeSetName)
+		if err := stacks.DeleteChangeSet(svc, *cs.ChangeSetId); err != nil {
+			return err
+		}
+		if newStack {
+			return stacks.Delete(svc, stackName)
+		}
+		return nil
+	}
+
+	if !yes {
+		ok, err := confirm(w, "Execute change set?")
+		if err != nil {
+			if discardErr := discard(); discardErr != nil {
+				return discardErr
+			}
+			if err == io.EOF {
+				return fmt.Errorf("No answer to confirm the change set, pass --yes to execute it without asking")
+			}
+			return fmt.Errorf("Failed to confirm the change set: %v", err)
+		}
+		if !ok {
+			if err = discard(); err != nil {
+				return err
+			}
+			return events.Declined(stackName)
+		}
+	}
+
+	t := time.Now()
+	if err := stacks.ExecuteChangeSet(svc, *cs.ChangeSetId); err != nil {
+		return err
+	}
+
+	printer := events.Printer(stackName, t)
+	defer printer.Stop()
+
+	interrupts := handleInterrupts(svc, stackName, printer)
+	defer interrupts.Stop()
+
+	return printer.Watch(svc, interrupts.Events(printer.Print))
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// confirm asks a yes/no question on stdin, anything other than yes is a no. The question is
+// written to w.
+func confirm(w io.Writer, question string) (bool, error) {
+	fmt.Fprintf(w, "%s [y/N]: ", question)
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// choose asks a question on stdin with a list of options and returns the option chosen, either
+// by name or by its first letter. The question is written to w.
+func choose(w io.Writer, question string, options ...string) (string, error) {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Fprintf(w, "%s [%s]: ", question, strings.Join(options, "/"))
+
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", err
+		}
+
+		answer := strings.ToLower(strings.TrimSpace(line))
+		for _, option := range options {
+			if answer == option || (len(answer) == 1 && strings.HasPrefix(option, answer)) {
+				return option, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/cmd/args"
+	"github.com/lox/parfait/stacks"
+	"github.com/lox/parfait/templates"
+	"gopkg.in/alecthomas/kingpin.v2"
+	yaml "gopkg.in/yaml.v3"
+)
+
+type renderFlags struct {
+	Vars       []string
+	VarsFiles  []string
+	Render     bool
+	RenderOnly bool
+}
+
+func addRenderFlags(cmd *kingpin.CmdClause) *renderFlags {
+	f := &renderFlags{}
+
+	cmd.Flag("var", "A variable for rendering the template in Key=Val form").
+		StringsVar(&f.Vars)
+
+	cmd.Flag("vars-file", "A JSON or YAML file of variables for rendering the template").
+		StringsVar(&f.VarsFiles)
+
+	cmd.Flag("render", "Render the template with Go text/template, implied by --var and --vars-file").
+		BoolVar(&f.Render)
+
+	cmd.Flag("render-only", "Print the rendered template and exit").
+		BoolVar(&f.RenderOnly)
+
+	return f
+}
+
+// Enabled returns whether the template should be rendered
+func (f *renderFlags) Enabled() bool {
+	return f.Render || f.RenderOnly || len(f.Vars) > 0 || len(f.VarsFiles) > 0
+}
+
+// Apply renders a template body with the vars from the flags and returns a template with the
+// rendered body. Templates are returned as-is if rendering isn't enabled.
+func (f *renderFlags) Apply(sess client.ConfigProvider, tpl *args.Template) (*args.Template, error) {
+	if !f.Enabled() {
+		return tpl, nil
+	}
+
+	if tpl.Body == "" {
+		if tpl.URL != "" {
+			return nil, fmt.Errorf("Templates in S3 can't be rendered, use a local file instead")
+		}
+		return nil, fmt.Errorf("A --tpl is required to render a template")
+	}
+
+	vars, err := loadTemplateVars(f.VarsFiles, f.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := templates.Render("template", tpl.Body, vars, func(stackName string) (map[string]string, error) {
+		return stacks.Outputs(cloudformation.New(sess), stackName)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to render template: %v", err)
+	}
+
+	return &args.Template{Body: body}, nil
+}
+
+// loadTemplateVars reads vars from vars files in order followed by Key=Val args, with later
+// values overriding earlier ones. Vars in files can be lists or maps as well as strings.
+func loadTemplateVars(files []string, rawVars []string) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var m map[string]interface{}
+		if err = yaml.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("Failed to parse vars file %s: %v", file, err)
+		}
+
+		for k, v := range m {
+			vars[k] = v
+		}
+	}
+
+	for _, arg := range rawVars {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid var %q, expected Key=Val", arg)
+		}
+		vars[parts[0]] = parts[1]
+	}
+
+	return vars, nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+)
+
+// resolveStackParams resolves references to other stacks' outputs in parameter values and
+// prints where each resolved value came from to w
+func resolveStackParams(sess client.ConfigProvider, params map[string]string, w io.Writer) (map[string]string, error) {
+	resolved, resolutions, err := stacks.ResolveParams(params, func(ref stacks.OutputRef) (map[string]string, error) {
+		refSess := sess
+		if ref.Region != "" || ref.Profile != "" {
+			var err error
+			if refSess, err = newSession(ref.Region, ref.Profile); err != nil {
+				return nil, err
+			}
+		}
+		return stacks.Outputs(cloudformation.New(refSess), ref.StackName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resolutions) > 0 {
+		fmt.Fprintf(w, "%-30s %-50s %-60s\n", "PARAMETER", "SOURCE", "VALUE")
+		for _, r := range resolutions {
+			fmt.Fprintf(w, "%-30s %-50s %-60s\n", r.Param, r.Ref.String(), r.Value)
+		}
+		fmt.Fprintln(w)
+	}
+
+	return resolved, nil
+}
+
+// newSession creates an AWS session for a specific region and/or profile, configured the same
+// way as the default session
+func newSession(region, profile string) (*session.Session, error) {
+	config := aws.Config{MaxRetries: aws.Int(25)}
+	if region != "" {
+		config.Region = aws.String(region)
+	}
+
+	return session.NewSessionWithOptions(session.Options{
+		Config:            config,
+		Profile:           profile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+}
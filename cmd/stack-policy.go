@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/cmd/args"
+	"github.com/lox/parfait/cmd/output"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureGetStackPolicy(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName string
+	var statements bool
+
+	cmd := app.Command("get-stack-policy", "Show the stack policy of a cloudformation stack")
+	cmd.Alias("policy")
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Flag("statements", "Show a row per policy statement instead of the policy, in the --output format").
+		BoolVar(&statements)
+
+	format := addOutputFlag(cmd)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		policy, err := stacks.GetPolicy(cloudformation.New(sess), stackName)
+		if err != nil {
+			return err
+		}
+
+		if policy == "" {
+			return fmt.Errorf("Stack %s has no stack policy", stackName)
+		}
+
+		// the policy as-is can be edited and passed back to set-stack-policy
+		if !statements {
+			fmt.Println(strings.TrimRight(policy, "\n"))
+			return nil
+		}
+
+		var doc struct {
+			Statement []map[string]interface{}
+		}
+		if err = json.Unmarshal([]byte(policy), &doc); err != nil {
+			return fmt.Errorf("Failed to parse stack policy: %v", err)
+		}
+
+		table := output.NewTable(
+			output.Column{Header: "EFFECT", Key: "Effect", Width: 6},
+			output.Column{Header: "ACTION", Key: "Action", Width: 30},
+			output.Column{Header: "PRINCIPAL", Key: "Principal", Width: 10},
+			output.Column{Header: "RESOURCE", Key: "Resource", Width: 50},
+			output.Column{Header: "CONDITION", Key: "Condition"},
+		)
+		for _, st := range doc.Statement {
+			table.Add(
+				policyValue(st["Effect"]),
+				policyNegatable(st, "Action"),
+				policyValue(st["Principal"]),
+				policyNegatable(st, "Resource"),
+				policyValue(st["Condition"]),
+			)
+		}
+
+		return table.Write(os.Stdout, *format)
+	})
+}
+
+// policyNegatable returns the value of a statement field that can also be given as NotAction
+// or NotResource, prefixing the latter with NOT
+func policyNegatable(statement map[string]interface{}, key string) string {
+	if v, ok := statement["Not"+key]; ok {
+		return "NOT " + policyValue(v)
+	}
+	return policyValue(statement[key])
+}
+
+// policyValue formats a value in a policy statement, joining lists and using json for objects
+func policyValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		items := []string{}
+		for _, item := range v {
+			items = append(items, policyValue(item))
+		}
+		return strings.Join(items, ",")
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func ConfigureSetStackPolicy(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName string
+
+	cmd := app.Command("set-stack-policy", "Set the stack policy of a cloudformation stack")
+
+	policy := args.TemplateSource(cmd.Flag("policy", "Either a file path or url to a stack policy").
+		Short('p').
+		Required())
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		if err := stacks.SetPolicy(cloudformation.New(sess), stackName, policy.Body, policy.URL); err != nil {
+			return err
+		}
+
+		fmt.Printf("Stack policy set on %s\n", stackName)
+		return nil
+	})
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureTagStack(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName string
+	var tags, tagsFiles, removeTags []string
+
+	cmd := app.Command("tag-stack", "Change the tags on a cloudformation stack, keeping the previous template and parameters")
+	cmd.Alias("tag")
+
+	cmd.Flag("tag", "A tag to set on the stack in Key=Val form").
+		StringsVar(&tags)
+
+	cmd.Flag("tags-file", "A JSON or YAML file of tags, either in aws cli format or a map of keys to values").
+		StringsVar(&tagsFiles)
+
+	cmd.Flag("remove-tag", "The key of a tag to remove from the stack").
+		StringsVar(&removeTags)
+
+	events := addEventFlags(cmd)
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		tags, err := loadStackTags(tagsFiles, tags)
+		if err != nil {
+			return err
+		}
+
+		if len(tags) == 0 && len(removeTags) == 0 {
+			return fmt.Errorf("No tags to set or remove")
+		}
+
+		t := time.Now()
+		svc := cloudformation.New(sess)
+
+		if err = stacks.UpdateTags(svc, stackName, tags, removeTags); err != nil {
+			return err
+		}
+
+		printer := events.Printer(stackName, t)
+		defer printer.Stop()
+
+		return printer.Watch(svc, printer.Print)
+	})
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// loadStackTags reads tags from tags files in order followed by Key=Val args, with later
+// values overriding earlier ones
+func loadStackTags(files []string, rawTags []string) (map[string]string, error) {
+	tags := map[string]string{}
+
+	for _, file := range files {
+		if err := readKeyValuesFile("Tag", file, tags, readTagsList); err != nil {
+			return nil, err
+		}
+	}
+
+	cliTags, err := parseKeyValues("Tag", rawTags)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range cliTags {
+		tags[k] = v
+	}
+
+	return tags, nil
+}
+
+type cliTag struct {
+	Key   string `yaml:"Key"`
+	Value string `yaml:"Value"`
+}
+
+// readTagsList reads the JSON tag list format that the aws cli uses
+func readTagsList(node *yaml.Node, tags map[string]string) error {
+	var list []cliTag
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+
+	for idx, tag := range list {
+		if tag.Key == "" {
+			return fmt.Errorf("Tag %d has no Key", idx)
+		}
+		tags[tag.Key] = tag.Value
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLoadingStackTags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parfait-tags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cliFormat := writeTempFile(t, dir, "tags.json", `[
+		{"Key": "env", "Value": "prod"},
+		{"Key": "team", "Value": "infra"}
+	]`)
+	flatFormat := writeTempFile(t, dir, "tags.yml", "team: platform\nowner: lox\n")
+
+	for _, tc := range []struct {
+		name     string
+		files    []string
+		args     []string
+		expected map[string]string
+	}{
+		{"args", nil, []string{"env=dev", "url=https://a?b=c"}, map[string]string{"env": "dev", "url": "https://a?b=c"}},
+		{"aws cli format", []string{cliFormat}, nil, map[string]string{"env": "prod", "team": "infra"}},
+		{"later files override earlier ones", []string{cliFormat, flatFormat}, nil,
+			map[string]string{"env": "prod", "team": "platform", "owner": "lox"}},
+		{"args override files", []string{cliFormat, flatFormat}, []string{"team=data"},
+			map[string]string{"env": "prod", "team": "data", "owner": "lox"}},
+	} {
+		tags, err := loadStackTags(tc.files, tc.args)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(tags, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, tags)
+		}
+	}
+
+	for _, args := range [][]string{{"env"}, {"=prod"}} {
+		if _, err := loadStackTags(nil, args); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
@@ -13,7 +13,9 @@ import (
 
 func ConfigureUpdateStack(app *kingpin.Application, sess client.ConfigProvider) {
 	var stackName string
-	var params []string
+	var params, paramsFiles []string
+	var tags, tagsFiles, removeTags []string
+	var changeSet, yes bool
 
 	cmd := app.Command("update-stack", "Update a cloudformation stack")
 	cmd.Alias("update")
@@ -21,6 +23,33 @@ func ConfigureUpdateStack(app *kingpin.Application, sess client.ConfigProvider)
 	tpl := args.TemplateSource(cmd.Flag("tpl", "Either a file path or url to a cloudformation template").
 		Short('t'))
 
+	policy := args.TemplateSource(cmd.Flag("stack-policy-during-update", "Either a file path or url to a temporary stack policy to use during the update"))
+
+	caps := addCapabilitiesFlags(cmd)
+	upload := addUploadFlags(cmd)
+	render := addRenderFlags(cmd)
+	events := addEventFlags(cmd)
+	events.addFormatFlag(cmd)
+
+	cmd.Flag("params-file", "A JSON or YAML file of parameters, either in aws cli format or a map of keys to values").
+		StringsVar(&paramsFiles)
+
+	cmd.Flag("tag", "A tag to set on the stack in Key=Val form").
+		StringsVar(&tags)
+
+	cmd.Flag("tags-file", "A JSON or YAML file of tags, either in aws cli format or a map of keys to values").
+		StringsVar(&tagsFiles)
+
+	cmd.Flag("remove-tag", "The key of an existing tag to remove from the stack").
+		StringsVar(&removeTags)
+
+	cmd.Flag("change-set", "Update the stack via a change set, showing the changes before executing them").
+		BoolVar(&changeSet)
+
+	cmd.Flag("yes", "Execute the change set without asking for confirmation").
+		Short('y').
+		BoolVar(&yes)
+
 	cmd.Arg("name", "The name of the cloudformation stack").
 		Required().
 		StringVar(&stackName)
@@ -28,32 +57,74 @@ func ConfigureUpdateStack(app *kingpin.Application, sess client.ConfigProvider)
 	cmd.Arg("params", "Parameters to the stack in Key=Val form").
 		StringsVar(&params)
 
-	cmd.Action(func(c *kingpin.ParseContext) error {
-		params, err := parseStackParams(params)
+	cmd.Action(events.Action(&stackName, func(c *kingpin.ParseContext) error {
+		tpl, err := render.Apply(sess, tpl)
+		if err != nil {
+			return err
+		}
+
+		if render.RenderOnly {
+			fmt.Print(tpl.Body)
+			return nil
+		}
+
+		params, err := loadStackParams(paramsFiles, params)
+		if err != nil {
+			return err
+		}
+
+		if params, err = resolveStackParams(sess, params, events.Log()); err != nil {
+			return err
+		}
+
+		tags, err := loadStackTags(tagsFiles, tags)
+		if err != nil {
+			return err
+		}
+
+		tpl, err = upload.Prepare(sess, stackName, tpl)
 		if err != nil {
 			return err
 		}
 
 		ctx := stacks.UpdateStackContext{
-			Params: params,
-			Body:   tpl.String(),
+			Params:       params,
+			Tags:         tags,
+			RemoveTags:   removeTags,
+			Body:         tpl.Body,
+			URL:          tpl.URL,
+			Capabilities: caps.Capabilities(),
+			DenyIAM:      caps.DenyIAM,
+
+			PolicyDuringUpdateBody: policy.Body,
+			PolicyDuringUpdateURL:  policy.URL,
 		}
 
 		t := time.Now()
 		svc := cloudformation.New(sess)
 
-		if err = stacks.Update(svc, stackName, ctx); err != nil {
-			if stacks.IsNoUpdateErr(err) {
-				fmt.Printf("No updates to be performed, stack is up to date\n")
-				return nil
+		if changeSet {
+			if !policy.IsEmpty() {
+				return fmt.Errorf("A stack policy during update can't be used with a change set")
 			}
+
+			cs, err := stacks.PlanUpdate(svc, stackName, ctx)
+			if err != nil {
+				return err
+			}
+			return applyChangeSet(svc, stackName, cs, false, yes, events)
+		}
+
+		if err = stacks.Update(svc, stackName, ctx); err != nil {
 			return err
 		}
 
-		return stacks.Watch(svc, stackName, func(event *cloudformation.StackEvent) {
-			if event.Timestamp.After(t) {
-				fmt.Printf("%s\n", stacks.FormatStackEvent(event))
-			}
-		})
-	})
+		printer := events.Printer(stackName, t)
+		defer printer.Stop()
+
+		interrupts := handleInterrupts(svc, stackName, printer)
+		defer interrupts.Stop()
+
+		return printer.Watch(svc, interrupts.Events(printer.Print))
+	}))
 }
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/lox/parfait/cmd/args"
+	"github.com/lox/parfait/s3upload"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+type uploadFlags struct {
+	Bucket   string
+	Prefix   string
+	Endpoint string
+}
+
+func addUploadFlags(cmd *kingpin.CmdClause) *uploadFlags {
+	f := &uploadFlags{}
+
+	cmd.Flag("upload-bucket", "An S3 bucket to upload templates to that are too large to pass as a body").
+		StringVar(&f.Bucket)
+
+	cmd.Flag("upload-prefix", "A prefix for the keys of uploaded templates").
+		StringVar(&f.Prefix)
+
+	cmd.Flag("s3-endpoint", "A custom endpoint for uploading templates to an S3-compatible server").
+		StringVar(&f.Endpoint)
+
+	return f
+}
+
+// Prepare uploads templates that are too large to be passed as a body and returns a template that
+// refers to the uploaded url. Other templates are returned as-is.
+func (f *uploadFlags) Prepare(sess client.ConfigProvider, stackName string, tpl *args.Template) (*args.Template, error) {
+	if tpl.URL != "" || len(tpl.Body) <= stacks.MaxTemplateBodySize {
+		return tpl, nil
+	}
+
+	if f.Bucket == "" {
+		return nil, fmt.Errorf("Template is %d bytes, which is over the %d byte limit for a template body. Use --upload-bucket to upload it to S3",
+			len(tpl.Body), stacks.MaxTemplateBodySize)
+	}
+
+	key := fmt.Sprintf("%s%s/%x.template", f.Prefix, stackName, sha256.Sum256([]byte(tpl.Body)))
+	uploader := s3upload.NewUploader(sess, f.Bucket, f.Endpoint)
+
+	log.Printf("Uploading template to %s", uploader.URL(key))
+	url, err := uploader.Upload(key, []byte(tpl.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	return &args.Template{URL: url}, nil
+}
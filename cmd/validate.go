@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/fatih/color"
+	"github.com/lox/parfait/cmd/args"
+	"github.com/lox/parfait/cmd/output"
+	"github.com/lox/parfait/lint"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureValidate(app *kingpin.Application, sess client.ConfigProvider) {
+	var offline bool
+
+	cmd := app.Command("validate", "Validate a cloudformation template and check it for common errors")
+	cmd.Alias("validate-template")
+
+	tpl := args.TemplateSource(cmd.Flag("tpl", "Either a file path or url to a cloudformation template").
+		Short('t').
+		Required())
+
+	render := addRenderFlags(cmd)
+
+	cmd.Flag("offline", "Only run offline checks, without validating the template with cloudformation").
+		BoolVar(&offline)
+
+	format := addOutputFlag(cmd)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		tpl, err := render.Apply(sess, tpl)
+		if err != nil {
+			return err
+		}
+
+		if render.RenderOnly {
+			fmt.Print(tpl.Body)
+			return nil
+		}
+
+		if !offline {
+			validate, err := stacks.Validate(cloudformation.New(sess), tpl.Body, tpl.URL)
+			if err != nil {
+				return err
+			}
+			if *format == output.TableFormat {
+				printValidation(validate)
+			}
+		}
+
+		// other formats only print the issues, so messages go to stderr
+		w := os.Stdout
+		if *format != output.TableFormat {
+			w = os.Stderr
+		}
+
+		if tpl.Body == "" {
+			fmt.Fprintf(w, "Skipping offline checks for template url %s\n", tpl.URL)
+			return nil
+		}
+
+		issues, err := lint.Lint(tpl.Body)
+		if err != nil {
+			return fmt.Errorf("Failed to parse template: %v", err)
+		}
+
+		if *format == output.TableFormat {
+			for _, issue := range issues {
+				if issue.Severity == lint.Error {
+					fmt.Printf("%s %s: %s\n", color.RedString(string(issue.Severity)), issue.Path, issue.Message)
+				} else {
+					fmt.Printf("%s %s: %s\n", color.YellowString(string(issue.Severity)), issue.Path, issue.Message)
+				}
+			}
+		} else {
+			table := output.NewTable(
+				output.Column{Header: "SEVERITY", Key: "Severity", Width: 8},
+				output.Column{Header: "PATH", Key: "Path", Width: 50},
+				output.Column{Header: "MESSAGE", Key: "Message"},
+			)
+			for _, issue := range issues {
+				table.Add(string(issue.Severity), issue.Path, issue.Message)
+			}
+			if err = table.Write(os.Stdout, *format); err != nil {
+				return err
+			}
+		}
+
+		if lint.HasErrors(issues) {
+			return fmt.Errorf("Template has errors")
+		}
+
+		fmt.Fprintf(w, "Template is valid\n")
+		return nil
+	})
+}
+
+func printValidation(validate *cloudformation.ValidateTemplateOutput) {
+	if validate.Description != nil {
+		fmt.Printf("Description: %s\n\n", *validate.Description)
+	}
+
+	if len(validate.Parameters) > 0 {
+		fmt.Printf("%-30s %-30s %-6s %s\n", "PARAMETER", "DEFAULT", "NOECHO", "DESCRIPTION")
+		for _, p := range validate.Parameters {
+			fmt.Printf("%-30s %-30s %-6t %s\n",
+				*p.ParameterKey,
+				aws.StringValue(p.DefaultValue),
+				aws.BoolValue(p.NoEcho),
+				aws.StringValue(p.Description),
+			)
+		}
+		fmt.Println()
+	}
+
+	if len(validate.Capabilities) > 0 {
+		fmt.Printf("Capabilities: %s\n", strings.Join(aws.StringValueSlice(validate.Capabilities), ", "))
+		if validate.CapabilitiesReason != nil {
+			fmt.Printf("Reason: %s\n", *validate.CapabilitiesReason)
+		}
+		fmt.Println()
+	}
+}
@@ -2,12 +2,11 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/fatih/color"
-	"github.com/lox/parfait/stacks"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -18,17 +17,29 @@ func ConfigureWatchStack(app *kingpin.Application, sess client.ConfigProvider) {
 	cmd.Alias("watch")
 	cmd.Alias("w")
 
+	events := addEventFlags(cmd)
+	events.addFormatFlag(cmd)
+
 	cmd.Arg("name", "The name of the cloudformation stack to watch").
 		StringVar(&stackName)
 
-	cmd.Action(func(c *kingpin.ParseContext) error {
-		err := stacks.Watch(cloudformation.New(sess), stackName, func(event *cloudformation.StackEvent) {
-			fmt.Printf("%s\n", stacks.FormatStackEvent(event))
-		})
-		if err != nil {
-			fmt.Printf("\n%v\n\n", color.RedString(err.Error()))
-			os.Exit(1)
+	cmd.Action(events.Action(&stackName, func(c *kingpin.ParseContext) error {
+		svc := cloudformation.New(sess)
+
+		printer := events.Printer(stackName, time.Time{})
+
+		interrupts := handleInterrupts(svc, stackName, printer)
+		defer interrupts.Stop()
+
+		err := printer.watch(svc, interrupts.Events(printer.Print))
+		printer.Stop()
+		if err == nil || events.JSON() {
+			return err
 		}
-		return nil
-	})
+
+		// the failure is explained here, main only needs to exit with its code
+		fmt.Printf("\n%v\n", color.RedString(err.Error()))
+		explainFailure(err)
+		return &reportedError{err}
+	}))
 }
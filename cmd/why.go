@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/stacks"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func ConfigureWhy(app *kingpin.Application, sess client.ConfigProvider) {
+	var stackName string
+
+	cmd := app.Command("why", "Show the resources that caused the most recent operation on a stack to fail")
+
+	cmd.Arg("name", "The name of the cloudformation stack").
+		Required().
+		StringVar(&stackName)
+
+	cmd.Action(func(c *kingpin.ParseContext) error {
+		svc := cloudformation.New(sess)
+
+		status, err := stacks.Status(svc, stackName)
+		if err != nil {
+			return err
+		}
+
+		failures, err := stacks.RootCauses(svc, stackName)
+		if err != nil {
+			return err
+		}
+
+		if len(failures) == 0 {
+			fmt.Printf("Stack %s is %s, no resources failed in its most recent operation\n",
+				stackName, stacks.FormatStackStatus(status))
+			return nil
+		}
+
+		fmt.Printf("Stack %s is %s\n", stackName, stacks.FormatStackStatus(status))
+		printRootCauses(failures)
+		return nil
+	})
+}
+
+// explainFailure prints the resources that caused an operation on a stack to fail, if the error
+// is an *stacks.OperationError, and returns the error
+func explainFailure(err error) error {
+	if opErr, ok := err.(*stacks.OperationError); ok && len(opErr.Failures) > 0 {
+		printRootCauses(opErr.Failures)
+	}
+	return err
+}
+
+func printRootCauses(failures []*cloudformation.StackEvent) {
+	fmt.Printf("\nCaused by:\n\n")
+	for _, f := range failures {
+		fmt.Printf("%s %s [%s] %s\n",
+			f.Timestamp.Local().Format("2006/01/02 15:04:05"),
+			*f.LogicalResourceId,
+			*f.ResourceType,
+			aws.StringValue(f.PhysicalResourceId),
+		)
+		fmt.Printf("    %s: %s\n\n", stacks.FormatStackStatus(*f.ResourceStatus), aws.StringValue(f.ResourceStatusReason))
+	}
+}
@@ -0,0 +1,316 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lox/parfait/templates"
+	yaml "gopkg.in/yaml.v3"
+)
+
+type Severity string
+
+const (
+	Error   Severity = "ERROR"
+	Warning Severity = "WARNING"
+)
+
+// Issue is a problem found in a template
+type Issue struct {
+	Severity Severity
+	Path     string
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s %s: %s", i.Severity, i.Path, i.Message)
+}
+
+// HasErrors returns whether any of the issues are errors
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// reference is a use of a parameter or resource via Ref, Fn::GetAtt or Fn::Sub
+type reference struct {
+	Target string
+	Attr   string
+	Path   string
+}
+
+// Lint parses a JSON or YAML template and checks it for references to parameters and resources
+// that don't exist, unused parameters and circular dependencies between resources
+func Lint(body string) ([]Issue, error) {
+	tpl, err := templates.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	params := keySet(tpl.Section("Parameters"))
+	resourcesNode := tpl.Section("Resources")
+	resources := keySet(resourcesNode)
+	hasTransform := tpl.Section("Transform") != nil
+
+	issues := []Issue{}
+	used := map[string]bool{}
+
+	if len(resources) == 0 {
+		issues = append(issues, Issue{Error, "Resources", "Template has no resources"})
+	}
+
+	// transforms generate resources, so references to them can't be checked
+	if hasTransform {
+		issues = append(issues, Issue{Warning, "Transform",
+			"Template uses a transform, references to generated resources aren't checked"})
+	}
+
+	checkRefs := func(refs []reference) {
+		for _, ref := range refs {
+			used[ref.Target] = true
+
+			switch {
+			case strings.HasPrefix(ref.Target, "AWS::"):
+			case ref.Attr == "" && params[ref.Target]:
+			case resources[ref.Target]:
+			case hasTransform:
+			case ref.Attr != "" && params[ref.Target]:
+				issues = append(issues, Issue{Error, ref.Path,
+					fmt.Sprintf("Fn::GetAtt target %s is a parameter, not a resource", ref.Target)})
+			case strings.HasPrefix(ref.Path, "Outputs."):
+				issues = append(issues, Issue{Error, ref.Path,
+					fmt.Sprintf("Output references missing resource %s", ref.Target)})
+			case ref.Attr != "":
+				issues = append(issues, Issue{Error, ref.Path,
+					fmt.Sprintf("Fn::GetAtt target %s doesn't exist", ref.Target)})
+			default:
+				issues = append(issues, Issue{Error, ref.Path,
+					fmt.Sprintf("Ref target %s isn't a parameter or resource", ref.Target)})
+			}
+		}
+	}
+
+	for _, section := range []string{"Rules", "Conditions", "Metadata", "Outputs"} {
+		if node := tpl.Section(section); node != nil {
+			checkRefs(findRefs(node, section))
+		}
+	}
+
+	deps := map[string][]string{}
+	if resourcesNode != nil {
+		for i := 0; i+1 < len(resourcesNode.Content); i += 2 {
+			name, resource := resourcesNode.Content[i].Value, resourcesNode.Content[i+1]
+			path := "Resources." + name
+
+			refs := findRefs(resource, path)
+			checkRefs(refs)
+
+			for _, ref := range refs {
+				if resources[ref.Target] {
+					deps[name] = append(deps[name], ref.Target)
+				}
+			}
+
+			for _, dep := range dependsOn(resource) {
+				if !resources[dep] {
+					issues = append(issues, Issue{Error, path + ".DependsOn",
+						fmt.Sprintf("DependsOn target %s doesn't exist", dep)})
+					continue
+				}
+				deps[name] = append(deps[name], dep)
+			}
+		}
+	}
+
+	for _, cycle := range findCycles(deps) {
+		issues = append(issues, Issue{Error, "Resources." + cycle[0],
+			fmt.Sprintf("Circular dependency between resources: %s", strings.Join(cycle, " -> "))})
+	}
+
+	unused := []string{}
+	for param := range params {
+		if !used[param] {
+			unused = append(unused, param)
+		}
+	}
+	sort.Strings(unused)
+
+	for _, param := range unused {
+		issues = append(issues, Issue{Warning, "Parameters." + param, "Parameter is never used"})
+	}
+
+	return issues, nil
+}
+
+func keySet(n *yaml.Node) map[string]bool {
+	keys := map[string]bool{}
+	for _, key := range templates.MapKeys(n) {
+		keys[key] = true
+	}
+	return keys
+}
+
+func dependsOn(resource *yaml.Node) []string {
+	node := templates.MapValue(resource, "DependsOn")
+	if node == nil {
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []string{node.Value}
+	case yaml.SequenceNode:
+		deps := []string{}
+		for _, item := range node.Content {
+			deps = append(deps, item.Value)
+		}
+		return deps
+	}
+	return nil
+}
+
+// findRefs returns all the references to parameters and resources within a node via Ref,
+// Fn::GetAtt and Fn::Sub. Short forms like !Ref are already expanded when the template is parsed.
+func findRefs(n *yaml.Node, path string) []reference {
+	refs := []reference{}
+
+	if n.Kind == yaml.MappingNode && len(n.Content) == 2 {
+		key, value := n.Content[0].Value, n.Content[1]
+		switch key {
+		case "Ref":
+			if value.Kind == yaml.ScalarNode {
+				return append(refs, reference{Target: value.Value, Path: path})
+			}
+		case "Fn::GetAtt":
+			return append(refs, getAttRef(value, path))
+		case "Fn::Sub":
+			return append(refs, subRefs(value, path)...)
+		}
+	}
+
+	for i, child := range n.Content {
+		childPath := path
+		if n.Kind == yaml.MappingNode && i%2 == 1 {
+			childPath = path + "." + n.Content[i-1].Value
+		} else if n.Kind == yaml.MappingNode {
+			continue
+		}
+		refs = append(refs, findRefs(child, childPath)...)
+	}
+
+	return refs
+}
+
+func getAttRef(n *yaml.Node, path string) reference {
+	if n.Kind == yaml.ScalarNode {
+		parts := strings.SplitN(n.Value, ".", 2)
+		if len(parts) == 2 {
+			return reference{Target: parts[0], Attr: parts[1], Path: path}
+		}
+		return reference{Target: n.Value, Attr: "?", Path: path}
+	}
+
+	if n.Kind == yaml.SequenceNode && len(n.Content) > 0 {
+		ref := reference{Target: n.Content[0].Value, Attr: "?", Path: path}
+		if len(n.Content) > 1 && n.Content[1].Kind == yaml.ScalarNode {
+			ref.Attr = n.Content[1].Value
+		}
+		return ref
+	}
+
+	return reference{Target: n.Value, Attr: "?", Path: path}
+}
+
+var subVarPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// subRefs returns the references in a Fn::Sub string, excluding ${!Literal} and any variables
+// that are defined in the variable map
+func subRefs(n *yaml.Node, path string) []reference {
+	refs := []reference{}
+	str := n
+	vars := map[string]bool{}
+
+	if n.Kind == yaml.SequenceNode && len(n.Content) > 0 {
+		str = n.Content[0]
+		if len(n.Content) > 1 {
+			vars = keySet(n.Content[1])
+			refs = append(refs, findRefs(n.Content[1], path)...)
+		}
+	}
+
+	if str.Kind != yaml.ScalarNode {
+		return refs
+	}
+
+	for _, match := range subVarPattern.FindAllStringSubmatch(str.Value, -1) {
+		name := strings.TrimSpace(match[1])
+		if strings.HasPrefix(name, "!") || vars[name] {
+			continue
+		}
+		parts := strings.SplitN(name, ".", 2)
+		ref := reference{Target: parts[0], Path: path}
+		if len(parts) == 2 {
+			ref.Attr = parts[1]
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+// findCycles returns each cycle in a dependency graph as a path that ends where it started
+func findCycles(deps map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	names := []string{}
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	state := map[string]int{}
+	stack := []string{}
+	cycles := [][]string{}
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, dep := range deps[name] {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dep {
+						cycle := append([]string{}, stack[i:]...)
+						cycles = append(cycles, append(cycle, dep))
+						break
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+	}
+
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+
+	return cycles
+}
@@ -0,0 +1,83 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLintingValidTemplate(t *testing.T) {
+	issues, err := Lint(`
+Parameters:
+  BucketName:
+    Type: String
+  Environment:
+    Type: String
+Rules:
+  ProdBucketName:
+    RuleCondition: !Equals [!Ref Environment, prod]
+    Assertions:
+      - Assert: !Not [!Equals [!Ref BucketName, ""]]
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Ref BucketName
+  Policy:
+    Type: AWS::S3::BucketPolicy
+    Properties:
+      Bucket: !Ref Bucket
+      PolicyDocument:
+        Statement:
+          - Resource: !Sub "${Bucket.Arn}/*"
+          - Resource: !Sub ["${Arn}/${!Literal}", {Arn: !GetAtt Bucket.Arn}]
+Outputs:
+  BucketArn:
+    Value: !GetAtt [Bucket, Arn]
+  Region:
+    Value: !Ref AWS::Region
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 0 {
+		t.Fatalf("Expected no issues, got %v", issues)
+	}
+}
+
+func TestLintingInvalidTemplate(t *testing.T) {
+	issues, err := Lint(`{
+		"Parameters": {
+			"Unused": {"Type": "String"}
+		},
+		"Resources": {
+			"A": {"Type": "AWS::SNS::Topic", "DependsOn": "B"},
+			"B": {"Type": "AWS::SNS::Topic", "DependsOn": ["A", "Missing"]},
+			"C": {"Type": "AWS::SNS::Topic", "Properties": {"TopicName": {"Ref": "Nope"}}},
+			"D": {"Type": "AWS::SNS::Topic", "Properties": {"TopicName": {"Fn::GetAtt": ["Nope", "Name"]}}}
+		},
+		"Outputs": {
+			"Topic": {"Value": {"Ref": "Gone"}}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Issue{
+		{Error, "Outputs.Topic.Value", "Output references missing resource Gone"},
+		{Error, "Resources.B.DependsOn", "DependsOn target Missing doesn't exist"},
+		{Error, "Resources.C.Properties.TopicName", "Ref target Nope isn't a parameter or resource"},
+		{Error, "Resources.D.Properties.TopicName", "Fn::GetAtt target Nope doesn't exist"},
+		{Error, "Resources.A", "Circular dependency between resources: A -> B -> A"},
+		{Warning, "Parameters.Unused", "Parameter is never used"},
+	}
+
+	if !reflect.DeepEqual(issues, expected) {
+		t.Fatalf("Expected %v, got %v", expected, issues)
+	}
+
+	if !HasErrors(issues) {
+		t.Fatal("Expected errors")
+	}
+}
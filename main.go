@@ -1,12 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/lox/parfait/cmd"
+	"github.com/lox/parfait/stacks"
 	"github.com/lox/parfait/version"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
@@ -37,10 +39,41 @@ func run(args []string, exit func(code int)) {
 	cmd.ConfigureWatchStack(app, sess)
 	cmd.ConfigureListStacks(app, sess)
 	cmd.ConfigureListStackOutputs(app, sess)
+	cmd.ConfigureGetOutput(app, sess)
+	cmd.ConfigureExec(app, sess)
+	cmd.ConfigureListExports(app, sess)
+	cmd.ConfigureListStackResources(app, sess)
+	cmd.ConfigureFindResource(app, sess)
+	cmd.ConfigureEstimateCost(app, sess)
+	cmd.ConfigureValidate(app, sess)
+	cmd.ConfigureConvertTemplate(app, sess)
 	cmd.ConfigureCreateStack(app, sess)
 	cmd.ConfigureUpdateStack(app, sess)
 	cmd.ConfigureDeleteStack(app, sess)
+	cmd.ConfigurePlanStack(app, sess)
+	cmd.ConfigureTagStack(app, sess)
+	cmd.ConfigureDeploy(app, sess)
+	cmd.ConfigureGetStackPolicy(app, sess)
+	cmd.ConfigureSetStackPolicy(app, sess)
+	cmd.ConfigureCancelUpdate(app, sess)
+	cmd.ConfigureContinueUpdateRollback(app, sess)
+	cmd.ConfigureWhy(app, sess)
 	cmd.ConfigureFollowLogs(app, sess)
 
-	kingpin.MustParse(app.Parse(args))
+	if _, err = app.Parse(args); err != nil {
+		// failed stack operations have their own exit codes, other errors exit 1. Having no
+		// updates to perform isn't a failure, so it's not shown as an error.
+		code := cmd.ExitCode(err)
+		if cmd.IsReported(err) {
+			exit(code)
+		} else if _, noUpdates := err.(*stacks.NoUpdatesError); noUpdates {
+			fmt.Println(err)
+			exit(code)
+		} else if code != 1 {
+			app.Errorf("%s", err)
+			exit(code)
+		} else {
+			app.Fatalf("%s, try --help", err)
+		}
+	}
 }
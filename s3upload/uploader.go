@@ -0,0 +1,87 @@
+package s3upload
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+// Uploader puts objects in an S3 bucket with signed requests, addressing the bucket by path so
+// that S3-compatible servers can be used via a custom endpoint
+type Uploader struct {
+	Bucket      string
+	Endpoint    string
+	Region      string
+	credentials *credentials.Credentials
+	client      *http.Client
+}
+
+// NewUploader creates an uploader for a bucket, if endpoint is empty the default S3 endpoint
+// for the session's region is used
+func NewUploader(sess client.ConfigProvider, bucket, endpoint string) *Uploader {
+	cfgs := []*aws.Config{}
+	if endpoint != "" {
+		cfgs = append(cfgs, &aws.Config{Endpoint: aws.String(endpoint)})
+	}
+
+	cfg := sess.ClientConfig("s3", cfgs...)
+
+	// global endpoints don't have a signing region
+	region := cfg.SigningRegion
+	if region == "" {
+		region = aws.StringValue(cfg.Config.Region)
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &Uploader{
+		Bucket:      bucket,
+		Endpoint:    strings.TrimSuffix(cfg.Endpoint, "/"),
+		Region:      region,
+		credentials: cfg.Config.Credentials,
+		client:      http.DefaultClient,
+	}
+}
+
+// URL returns the path-style url of an object in the bucket
+func (u *Uploader) URL(key string) string {
+	return fmt.Sprintf("%s/%s/%s", u.Endpoint, u.Bucket, strings.TrimPrefix(key, "/"))
+}
+
+// Upload puts the body in the bucket under the given key and returns the url of the object
+func (u *Uploader) Upload(key string, body []byte) (string, error) {
+	objectURL := u.URL(key)
+
+	req, err := http.NewRequest("PUT", objectURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+
+	signer := v4.NewSigner(u.credentials, func(s *v4.Signer) {
+		s.DisableURIPathEscaping = true
+	})
+
+	if _, err = signer.Sign(req, bytes.NewReader(body), "s3", u.Region, time.Now()); err != nil {
+		return "", err
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Upload to %s failed with status %s", objectURL, resp.Status)
+	}
+
+	return objectURL, nil
+}
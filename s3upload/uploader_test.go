@@ -0,0 +1,51 @@
+package s3upload
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestUploadingToCustomEndpoint(t *testing.T) {
+	var gotPath, gotBody, gotAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		gotPath, gotBody, gotAuth = r.URL.Path, string(b), r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	sess := session.New(&aws.Config{
+		Region:      aws.String("ap-southeast-2"),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+	})
+
+	u := NewUploader(sess, "my-bucket", server.URL)
+
+	objectURL, err := u.Upload("templates/stack.json", []byte(`{"Resources":{}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := server.URL + "/my-bucket/templates/stack.json"; objectURL != expected {
+		t.Fatalf("Expected url %q, got %q", expected, objectURL)
+	}
+
+	if gotPath != "/my-bucket/templates/stack.json" {
+		t.Fatalf("Unexpected path %q", gotPath)
+	}
+
+	if gotBody != `{"Resources":{}}` {
+		t.Fatalf("Unexpected body %q", gotBody)
+	}
+
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/") {
+		t.Fatalf("Expected a signed request, got %q", gotAuth)
+	}
+}
@@ -0,0 +1,78 @@
+package stacks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+func isIAMCapability(c string) bool {
+	return c == cloudformation.CapabilityCapabilityIam ||
+		c == cloudformation.CapabilityCapabilityNamedIam
+}
+
+// capabilityAutoExpand isn't in the vendored sdk yet
+const capabilityAutoExpand = "CAPABILITY_AUTO_EXPAND"
+
+// capabilities returns the capabilities to use for a template. By default these are the ones that
+// ValidateTemplate reports the template needs, plus CAPABILITY_AUTO_EXPAND for templates that declare
+// transforms. Requested capabilities replace them, unless they are prefixed with + in which case they
+// are added to them. If IAM is denied, templates that need IAM capabilities are refused.
+func capabilities(validate *cloudformation.ValidateTemplateOutput, requested []string, denyIAM bool) ([]*string, error) {
+	required := []string{}
+	for _, c := range validate.Capabilities {
+		required = append(required, *c)
+	}
+	if len(validate.DeclaredTransforms) > 0 {
+		required = append(required, capabilityAutoExpand)
+	}
+
+	replaced, added := []string{}, []string{}
+	for _, c := range requested {
+		if strings.HasPrefix(c, "+") {
+			added = append(added, strings.TrimPrefix(c, "+"))
+		} else {
+			replaced = append(replaced, c)
+		}
+	}
+
+	caps := required
+	if len(replaced) > 0 {
+		caps = replaced
+	}
+	for _, c := range added {
+		if !containsString(caps, c) {
+			caps = append(caps, c)
+		}
+	}
+
+	if denyIAM {
+		iam := []string{}
+		for _, c := range append(required, caps...) {
+			if isIAMCapability(c) {
+				iam = append(iam, c)
+			}
+		}
+		if len(iam) > 0 {
+			reason := ""
+			if validate.CapabilitiesReason != nil {
+				reason = ": " + *validate.CapabilitiesReason
+			}
+			return nil, fmt.Errorf("Template requires IAM capabilities (%s) which are denied%s",
+				strings.Join(iam, ", "), reason)
+		}
+	}
+
+	return aws.StringSlice(caps), nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package stacks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+func TestCapabilitiesDefaultToTemplateRequirements(t *testing.T) {
+	validate := &cloudformation.ValidateTemplateOutput{
+		Capabilities: aws.StringSlice([]string{"CAPABILITY_IAM"}),
+	}
+
+	caps, err := capabilities(validate, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(aws.StringValueSlice(caps), []string{"CAPABILITY_IAM"}) {
+		t.Fatalf("Unexpected capabilities %v", aws.StringValueSlice(caps))
+	}
+
+	caps, err = capabilities(validate, []string{"CAPABILITY_NAMED_IAM"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(aws.StringValueSlice(caps), []string{"CAPABILITY_NAMED_IAM"}) {
+		t.Fatalf("Unexpected capabilities %v", aws.StringValueSlice(caps))
+	}
+}
+
+func TestDenyingIAMCapabilities(t *testing.T) {
+	validate := &cloudformation.ValidateTemplateOutput{
+		Capabilities: aws.StringSlice([]string{"CAPABILITY_NAMED_IAM"}),
+	}
+
+	if _, err := capabilities(validate, nil, true); err == nil {
+		t.Fatal("Expected an error for a template that requires IAM")
+	}
+
+	if _, err := capabilities(&cloudformation.ValidateTemplateOutput{}, nil, true); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCapabilitiesForTransforms(t *testing.T) {
+	validate := &cloudformation.ValidateTemplateOutput{
+		Capabilities:       aws.StringSlice([]string{"CAPABILITY_IAM"}),
+		DeclaredTransforms: aws.StringSlice([]string{"AWS::Serverless-2016-10-31"}),
+	}
+
+	caps, err := capabilities(validate, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"CAPABILITY_IAM", "CAPABILITY_AUTO_EXPAND"}
+	if !reflect.DeepEqual(aws.StringValueSlice(caps), expected) {
+		t.Fatalf("Expected %v, got %v", expected, aws.StringValueSlice(caps))
+	}
+}
+
+func TestAddingCapabilities(t *testing.T) {
+	validate := &cloudformation.ValidateTemplateOutput{
+		Capabilities: aws.StringSlice([]string{"CAPABILITY_IAM"}),
+	}
+
+	for _, tc := range []struct {
+		requested []string
+		expected  []string
+	}{
+		{[]string{"+CAPABILITY_NAMED_IAM"}, []string{"CAPABILITY_IAM", "CAPABILITY_NAMED_IAM"}},
+		{[]string{"+CAPABILITY_IAM"}, []string{"CAPABILITY_IAM"}},
+		{[]string{"CAPABILITY_NAMED_IAM", "+CAPABILITY_AUTO_EXPAND"}, []string{"CAPABILITY_NAMED_IAM", "CAPABILITY_AUTO_EXPAND"}},
+	} {
+		caps, err := capabilities(validate, tc.requested, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(aws.StringValueSlice(caps), tc.expected) {
+			t.Errorf("%v: expected %v, got %v", tc.requested, tc.expected, aws.StringValueSlice(caps))
+		}
+	}
+
+	if _, err := capabilities(&cloudformation.ValidateTemplateOutput{}, []string{"+CAPABILITY_IAM"}, true); err == nil {
+		t.Fatal("Expected an error for adding IAM capabilities when IAM is denied")
+	}
+}
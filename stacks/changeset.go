@@ -0,0 +1,151 @@
+package stacks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// PlanCreate creates a change set for a stack that doesn't exist yet and waits for it to be ready
+func PlanCreate(svc cfnInterface, name string, ctx CreateStackContext) (*cloudformation.DescribeChangeSetOutput, error) {
+	validate, err := validateTemplate(svc, ctx.Body, ctx.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	caps, err := capabilities(validate, ctx.Capabilities, ctx.DenyIAM)
+	if err != nil {
+		return nil, err
+	}
+
+	body, url := templateSource(ctx.Body, ctx.URL)
+
+	resp, err := svc.CreateChangeSet(&cloudformation.CreateChangeSetInput{
+		StackName:     aws.String(name),
+		ChangeSetName: aws.String(changeSetName()),
+		ChangeSetType: aws.String(cloudformation.ChangeSetTypeCreate),
+		Capabilities:  caps,
+		Parameters:    buildParams(ctx.Params),
+		Tags:          buildTags(ctx.Tags),
+		TemplateBody:  body,
+		TemplateURL:   url,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return WaitForChangeSet(svc, *resp.Id)
+}
+
+// PlanUpdate creates a change set for an existing stack and waits for it to be ready
+func PlanUpdate(svc cfnInterface, name string, ctx UpdateStackContext) (*cloudformation.DescribeChangeSetOutput, error) {
+	input, err := updateInput(svc, name, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.CreateChangeSet(&cloudformation.CreateChangeSetInput{
+		StackName:     aws.String(name),
+		ChangeSetName: aws.String(changeSetName()),
+		ChangeSetType: aws.String(cloudformation.ChangeSetTypeUpdate),
+		Capabilities:  input.Capabilities,
+		Parameters:    input.Parameters,
+		Tags:          input.Tags,
+		TemplateBody:  input.TemplateBody,
+		TemplateURL:   input.TemplateURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return WaitForChangeSet(svc, *resp.Id)
+}
+
+// changeSetPollInterval is how often change sets are checked whilst they're being created
+var changeSetPollInterval = 2 * time.Second
+
+func changeSetName() string {
+	return fmt.Sprintf("parfait-%d", time.Now().Unix())
+}
+
+// WaitForChangeSet polls a change set until it has finished being created and returns it with all changes
+func WaitForChangeSet(svc cfnInterface, id string) (*cloudformation.DescribeChangeSetOutput, error) {
+	for {
+		cs, err := DescribeChangeSet(svc, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch *cs.Status {
+		case cloudformation.ChangeSetStatusCreateComplete:
+			return cs, nil
+		case cloudformation.ChangeSetStatusFailed:
+			if IsEmptyChangeSet(cs) {
+				return cs, nil
+			}
+			reason := ""
+			if cs.StatusReason != nil {
+				reason = *cs.StatusReason
+			}
+			return nil, fmt.Errorf("Change set failed: %s", reason)
+		}
+
+		time.Sleep(changeSetPollInterval)
+	}
+}
+
+// DescribeChangeSet returns a change set with all pages of changes
+func DescribeChangeSet(svc cfnInterface, id string) (*cloudformation.DescribeChangeSetOutput, error) {
+	var result *cloudformation.DescribeChangeSetOutput
+	var nextToken *string
+
+	for {
+		resp, err := svc.DescribeChangeSet(&cloudformation.DescribeChangeSetInput{
+			ChangeSetName: aws.String(id),
+			NextToken:     nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if result == nil {
+			result = resp
+		} else {
+			result.Changes = append(result.Changes, resp.Changes...)
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	result.NextToken = nil
+	return result, nil
+}
+
+// IsEmptyChangeSet returns whether a change set failed because there was nothing to change
+func IsEmptyChangeSet(cs *cloudformation.DescribeChangeSetOutput) bool {
+	if cs.Status == nil || *cs.Status != cloudformation.ChangeSetStatusFailed || cs.StatusReason == nil {
+		return false
+	}
+	return strings.Contains(*cs.StatusReason, "didn't contain changes") ||
+		strings.Contains(*cs.StatusReason, "No updates are to be performed")
+}
+
+func ExecuteChangeSet(svc cfnInterface, id string) error {
+	_, err := svc.ExecuteChangeSet(&cloudformation.ExecuteChangeSetInput{
+		ChangeSetName: aws.String(id),
+	})
+	return err
+}
+
+func DeleteChangeSet(svc cfnInterface, id string) error {
+	_, err := svc.DeleteChangeSet(&cloudformation.DeleteChangeSetInput{
+		ChangeSetName: aws.String(id),
+	})
+	return err
+}
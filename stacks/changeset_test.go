@@ -0,0 +1,146 @@
+package stacks
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+type changeSetFake struct {
+	cfnInterface
+	// polls are the responses for each time the change set is described, each with its pages
+	polls [][]*cloudformation.DescribeChangeSetOutput
+}
+
+func (f *changeSetFake) DescribeChangeSet(input *cloudformation.DescribeChangeSetInput) (*cloudformation.DescribeChangeSetOutput, error) {
+	poll := f.polls[0]
+	page := 0
+	if input.NextToken != nil {
+		page = int((*input.NextToken)[0] - '0')
+	}
+	if page == len(poll)-1 && len(f.polls) > 1 {
+		f.polls = f.polls[1:]
+	}
+
+	// copy so that appending changes doesn't change the fake's pages
+	resp := *poll[page]
+	if page < len(poll)-1 {
+		resp.NextToken = aws.String(string(rune('0' + page + 1)))
+	}
+	return &resp, nil
+}
+
+func changeSetPage(status, reason string, resources ...string) *cloudformation.DescribeChangeSetOutput {
+	page := &cloudformation.DescribeChangeSetOutput{Status: aws.String(status)}
+	if reason != "" {
+		page.StatusReason = aws.String(reason)
+	}
+	for _, r := range resources {
+		page.Changes = append(page.Changes, &cloudformation.Change{
+			ResourceChange: &cloudformation.ResourceChange{LogicalResourceId: aws.String(r)},
+		})
+	}
+	return page
+}
+
+func changedResources(cs *cloudformation.DescribeChangeSetOutput) []string {
+	resources := []string{}
+	for _, c := range cs.Changes {
+		resources = append(resources, *c.ResourceChange.LogicalResourceId)
+	}
+	return resources
+}
+
+func TestDescribingChangeSetPages(t *testing.T) {
+	fake := &changeSetFake{polls: [][]*cloudformation.DescribeChangeSetOutput{{
+		changeSetPage("CREATE_COMPLETE", "", "Bucket", "Queue"),
+		changeSetPage("CREATE_COMPLETE", "", "Topic"),
+		changeSetPage("CREATE_COMPLETE", "", "Role"),
+	}}}
+
+	cs, err := DescribeChangeSet(fake, "cs-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"Bucket", "Queue", "Topic", "Role"}; !reflect.DeepEqual(changedResources(cs), expected) {
+		t.Fatalf("Expected changes %v, got %v", expected, changedResources(cs))
+	}
+	if cs.NextToken != nil {
+		t.Fatalf("Expected no next token, got %q", *cs.NextToken)
+	}
+}
+
+func TestWaitingForChangeSet(t *testing.T) {
+	defer func(interval time.Duration) { changeSetPollInterval = interval }(changeSetPollInterval)
+	changeSetPollInterval = 0
+
+	for _, tc := range []struct {
+		name      string
+		polls     [][]*cloudformation.DescribeChangeSetOutput
+		resources []string
+		err       bool
+	}{
+		{
+			name: "complete after polling",
+			polls: [][]*cloudformation.DescribeChangeSetOutput{
+				{changeSetPage("CREATE_PENDING", "")},
+				{changeSetPage("CREATE_IN_PROGRESS", "")},
+				{changeSetPage("CREATE_COMPLETE", "", "Bucket"), changeSetPage("CREATE_COMPLETE", "", "Queue")},
+			},
+			resources: []string{"Bucket", "Queue"},
+		},
+		{
+			name: "empty",
+			polls: [][]*cloudformation.DescribeChangeSetOutput{
+				{changeSetPage("FAILED", "The submitted information didn't contain changes. Submit different information to create a change set.")},
+			},
+			resources: []string{},
+		},
+		{
+			name: "failed",
+			polls: [][]*cloudformation.DescribeChangeSetOutput{
+				{changeSetPage("CREATE_IN_PROGRESS", "")},
+				{changeSetPage("FAILED", "Template format error")},
+			},
+			err: true,
+		},
+	} {
+		cs, err := WaitForChangeSet(&changeSetFake{polls: tc.polls}, "cs-1")
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(changedResources(cs), tc.resources) {
+			t.Errorf("%s: expected changes %v, got %v", tc.name, tc.resources, changedResources(cs))
+		}
+	}
+}
+
+func TestEmptyChangeSets(t *testing.T) {
+	for _, tc := range []struct {
+		cs    *cloudformation.DescribeChangeSetOutput
+		empty bool
+	}{
+		{changeSetPage("FAILED", "The submitted information didn't contain changes."), true},
+		{changeSetPage("FAILED", "No updates are to be performed."), true},
+		{changeSetPage("FAILED", "Template format error"), false},
+		{changeSetPage("FAILED", ""), false},
+		{changeSetPage("CREATE_COMPLETE", "didn't contain changes"), false},
+		{&cloudformation.DescribeChangeSetOutput{}, false},
+	} {
+		if empty := IsEmptyChangeSet(tc.cs); empty != tc.empty {
+			t.Errorf("Expected IsEmptyChangeSet to be %v for %s: %s", tc.empty,
+				aws.StringValue(tc.cs.Status), aws.StringValue(tc.cs.StatusReason))
+		}
+	}
+}
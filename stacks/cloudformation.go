@@ -3,6 +3,7 @@ package stacks
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -13,12 +14,25 @@ type cfnInterface interface {
 	DescribeStacksPages(*cloudformation.DescribeStacksInput, func(*cloudformation.DescribeStacksOutput, bool) bool) error
 	DescribeStackEventsPages(*cloudformation.DescribeStackEventsInput, func(*cloudformation.DescribeStackEventsOutput, bool) bool) error
 	DescribeStacks(*cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error)
+	ListStacksPages(*cloudformation.ListStacksInput, func(*cloudformation.ListStacksOutput, bool) bool) error
 	CreateStack(*cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error)
 	DeleteStack(*cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error)
 	UpdateStack(*cloudformation.UpdateStackInput) (*cloudformation.UpdateStackOutput, error)
 	GetTemplate(input *cloudformation.GetTemplateInput) (*cloudformation.GetTemplateOutput, error)
 	ValidateTemplate(input *cloudformation.ValidateTemplateInput) (*cloudformation.ValidateTemplateOutput, error)
 	GetTemplateSummary(input *cloudformation.GetTemplateSummaryInput) (*cloudformation.GetTemplateSummaryOutput, error)
+	CreateChangeSet(input *cloudformation.CreateChangeSetInput) (*cloudformation.CreateChangeSetOutput, error)
+	DescribeChangeSet(input *cloudformation.DescribeChangeSetInput) (*cloudformation.DescribeChangeSetOutput, error)
+	ExecuteChangeSet(input *cloudformation.ExecuteChangeSetInput) (*cloudformation.ExecuteChangeSetOutput, error)
+	DeleteChangeSet(input *cloudformation.DeleteChangeSetInput) (*cloudformation.DeleteChangeSetOutput, error)
+	GetStackPolicy(input *cloudformation.GetStackPolicyInput) (*cloudformation.GetStackPolicyOutput, error)
+	SetStackPolicy(input *cloudformation.SetStackPolicyInput) (*cloudformation.SetStackPolicyOutput, error)
+	CancelUpdateStack(input *cloudformation.CancelUpdateStackInput) (*cloudformation.CancelUpdateStackOutput, error)
+	ContinueUpdateRollback(input *cloudformation.ContinueUpdateRollbackInput) (*cloudformation.ContinueUpdateRollbackOutput, error)
+	ListExports(input *cloudformation.ListExportsInput) (*cloudformation.ListExportsOutput, error)
+	ListStackResourcesPages(*cloudformation.ListStackResourcesInput, func(*cloudformation.ListStackResourcesOutput, bool) bool) error
+	DescribeStackResources(input *cloudformation.DescribeStackResourcesInput) (*cloudformation.DescribeStackResourcesOutput, error)
+	EstimateTemplateCost(input *cloudformation.EstimateTemplateCostInput) (*cloudformation.EstimateTemplateCostOutput, error)
 }
 
 func FindAll(svc cfnInterface) (stacks []*cloudformation.Stack, err error) {
@@ -26,7 +40,7 @@ func FindAll(svc cfnInterface) (stacks []*cloudformation.Stack, err error) {
 		for _, s := range page.Stacks {
 			stacks = append(stacks, s)
 		}
-		return last
+		return true
 	})
 	return
 }
@@ -38,7 +52,7 @@ func FindAllActive(svc cfnInterface) (stacks []*cloudformation.Stack, err error)
 				stacks = append(stacks, s)
 			}
 		}
-		return last
+		return true
 	})
 	return
 }
@@ -54,35 +68,42 @@ func FindByName(svc cfnInterface, stackName string) (stacks []*cloudformation.St
 				stacks = append(stacks, s)
 			}
 		}
-		return last
+		return true
 	})
 	return
 }
 
 type CreateStackContext struct {
 	Params          map[string]string
+	Tags            map[string]string
 	Body            string
+	URL             string
 	DisableRollback bool
+	Capabilities    []string
+	DenyIAM         bool
 }
 
 func Create(svc cfnInterface, name string, ctx CreateStackContext) error {
-	paramsSlice := []*cloudformation.Parameter{}
-	for k, v := range ctx.Params {
-		paramsSlice = append(paramsSlice, &cloudformation.Parameter{
-			ParameterKey:   aws.String(k),
-			ParameterValue: aws.String(v),
-		})
+	validate, err := validateTemplate(svc, ctx.Body, ctx.URL)
+	if err != nil {
+		return err
 	}
 
-	_, err := svc.CreateStack(&cloudformation.CreateStackInput{
-		StackName: aws.String(name),
-		Capabilities: []*string{
-			aws.String("CAPABILITY_IAM"),
-			aws.String("CAPABILITY_NAMED_IAM"),
-		},
+	caps, err := capabilities(validate, ctx.Capabilities, ctx.DenyIAM)
+	if err != nil {
+		return err
+	}
+
+	body, url := templateSource(ctx.Body, ctx.URL)
+
+	_, err = svc.CreateStack(&cloudformation.CreateStackInput{
+		StackName:       aws.String(name),
+		Capabilities:    caps,
 		DisableRollback: aws.Bool(ctx.DisableRollback),
-		Parameters:      paramsSlice,
-		TemplateBody:    aws.String(ctx.Body),
+		Parameters:      buildParams(ctx.Params),
+		Tags:            buildTags(ctx.Tags),
+		TemplateBody:    body,
+		TemplateURL:     url,
 	})
 	if err != nil {
 		return err
@@ -91,42 +112,133 @@ func Create(svc cfnInterface, name string, ctx CreateStackContext) error {
 }
 
 type UpdateStackContext struct {
-	Params map[string]string
-	Body   string
+	Params       map[string]string
+	Tags         map[string]string
+	RemoveTags   []string
+	Body         string
+	URL          string
+	Capabilities []string
+	DenyIAM      bool
+
+	// an optional temporary stack policy for the duration of the update
+	PolicyDuringUpdateBody string
+	PolicyDuringUpdateURL  string
 }
 
 func Update(svc cfnInterface, name string, ctx UpdateStackContext) error {
-	paramsSlice := []*cloudformation.Parameter{}
-	for k, v := range ctx.Params {
-		paramsSlice = append(paramsSlice, &cloudformation.Parameter{
-			ParameterKey:   aws.String(k),
-			ParameterValue: aws.String(v),
-		})
+	input, err := updateInput(svc, name, ctx)
+	if err != nil {
+		return err
+	}
+
+	policyBody, policyURL := policySource(ctx.PolicyDuringUpdateBody, ctx.PolicyDuringUpdateURL)
+
+	_, err = svc.UpdateStack(&cloudformation.UpdateStackInput{
+		StackName:                   aws.String(name),
+		Capabilities:                input.Capabilities,
+		Parameters:                  input.Parameters,
+		Tags:                        input.Tags,
+		TemplateBody:                input.TemplateBody,
+		TemplateURL:                 input.TemplateURL,
+		StackPolicyDuringUpdateBody: policyBody,
+		StackPolicyDuringUpdateURL:  policyURL,
+	})
+	if IsNoUpdateErr(err) {
+		return &NoUpdatesError{StackName: name}
 	}
+	return err
+}
 
-	if ctx.Body == "" {
+// updateInput works out the template, parameters, capabilities and tags for an update of an
+// existing stack, shared between direct updates and change sets
+func updateInput(svc cfnInterface, name string, ctx UpdateStackContext) (*cloudformation.UpdateStackInput, error) {
+	if ctx.Body == "" && ctx.URL == "" {
 		log.Printf("Reading previous template")
 		resp, err := svc.GetTemplate(&cloudformation.GetTemplateInput{
 			StackName: aws.String(name),
 		})
 		if err != nil {
-			return err
+			return nil, err
 		}
 		ctx.Body = *resp.TemplateBody
 	}
 
-	// validate to get parameters
-	validate, err := svc.ValidateTemplate(&cloudformation.ValidateTemplateInput{
-		TemplateBody: aws.String(ctx.Body),
-	})
+	validate, err := validateTemplate(svc, ctx.Body, ctx.URL)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	caps, err := capabilities(validate, ctx.Capabilities, ctx.DenyIAM)
+	if err != nil {
+		return nil, err
+	}
+
+	paramsSlice, err := updateParams(svc, name, ctx.Params, validate)
+	if err != nil {
+		return nil, err
+	}
+
+	tagsSlice, err := updateTags(svc, name, ctx.Tags, ctx.RemoveTags)
+	if err != nil {
+		return nil, err
+	}
+
+	body, url := templateSource(ctx.Body, ctx.URL)
+
+	return &cloudformation.UpdateStackInput{
+		StackName:    aws.String(name),
+		Capabilities: caps,
+		Parameters:   paramsSlice,
+		Tags:         tagsSlice,
+		TemplateBody: body,
+		TemplateURL:  url,
+	}, nil
+}
+
+// MaxTemplateBodySize is the largest template that can be passed as a body rather than a url
+const MaxTemplateBodySize = 51200
+
+// templateSource returns either a template body or a url for the api, a url is used if present
+func templateSource(body, url string) (*string, *string) {
+	if url != "" {
+		return nil, aws.String(url)
+	}
+	return aws.String(body), nil
+}
+
+// Validate validates a template with cloudformation, returning its parameters and capabilities
+func Validate(svc cfnInterface, body, url string) (*cloudformation.ValidateTemplateOutput, error) {
+	return validateTemplate(svc, body, url)
+}
+
+func validateTemplate(svc cfnInterface, body, url string) (*cloudformation.ValidateTemplateOutput, error) {
+	bodyInput, urlInput := templateSource(body, url)
+	return svc.ValidateTemplate(&cloudformation.ValidateTemplateInput{
+		TemplateBody: bodyInput,
+		TemplateURL:  urlInput,
+	})
+}
+
+func buildParams(params map[string]string) []*cloudformation.Parameter {
+	paramsSlice := []*cloudformation.Parameter{}
+	for k, v := range params {
+		paramsSlice = append(paramsSlice, &cloudformation.Parameter{
+			ParameterKey:   aws.String(k),
+			ParameterValue: aws.String(v),
+		})
 	}
+	return paramsSlice
+}
+
+// updateParams builds the parameters for an update of an existing stack, using previous
+// values for any parameters in the validated template that aren't provided
+func updateParams(svc cfnInterface, name string, params map[string]string, validate *cloudformation.ValidateTemplateOutput) ([]*cloudformation.Parameter, error) {
+	paramsSlice := buildParams(params)
 
 	// lookup previous parameters so we don't use previous values that don't exist
 	previousParams, err := Parameters(svc, name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// use previous values for any missing params
@@ -136,7 +248,7 @@ func Update(svc cfnInterface, name string, ctx UpdateStackContext) error {
 			// log.Printf("Skipping previous value for %s, it didn't exist", *param.ParameterKey)
 			continue
 		}
-		if _, hasParam := ctx.Params[*param.ParameterKey]; !hasParam {
+		if _, hasParam := params[*param.ParameterKey]; !hasParam {
 			log.Printf("Using previous value %q for %s", previousValue, *param.ParameterKey)
 			paramsSlice = append(paramsSlice, &cloudformation.Parameter{
 				ParameterKey:     param.ParameterKey,
@@ -145,16 +257,7 @@ func Update(svc cfnInterface, name string, ctx UpdateStackContext) error {
 		}
 	}
 
-	_, err = svc.UpdateStack(&cloudformation.UpdateStackInput{
-		StackName: aws.String(name),
-		Capabilities: []*string{
-			aws.String("CAPABILITY_IAM"),
-			aws.String("CAPABILITY_NAMED_IAM"),
-		},
-		Parameters:   paramsSlice,
-		TemplateBody: aws.String(ctx.Body),
-	})
-	return err
+	return paramsSlice, nil
 }
 
 func Delete(svc cfnInterface, name string) error {
@@ -165,6 +268,46 @@ func Delete(svc cfnInterface, name string) error {
 	return err
 }
 
+// CancelUpdate cancels an in-progress update, which rolls the stack back to its previous state
+func CancelUpdate(svc cfnInterface, name string) error {
+	_, err := svc.CancelUpdateStack(&cloudformation.CancelUpdateStackInput{
+		StackName: aws.String(name),
+	})
+
+	return err
+}
+
+// EstimateCost returns a url to the AWS Simple Monthly Calculator for a template and parameters
+func EstimateCost(svc cfnInterface, body, url string, params map[string]string) (string, error) {
+	bodyInput, urlInput := templateSource(body, url)
+
+	resp, err := svc.EstimateTemplateCost(&cloudformation.EstimateTemplateCostInput{
+		Parameters:   buildParams(params),
+		TemplateBody: bodyInput,
+		TemplateURL:  urlInput,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(resp.Url), nil
+}
+
+// Template returns the current template body of a stack
+func Template(svc cfnInterface, name string) (string, error) {
+	resp, err := svc.GetTemplate(&cloudformation.GetTemplateInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(resp.TemplateBody), nil
+}
+
+// NoEchoValue is the value DescribeStacks returns in place of the values of NoEcho parameters
+const NoEchoValue = "****"
+
 func Parameters(svc cfnInterface, name string) (map[string]string, error) {
 	templateSummary, err := svc.GetTemplateSummary(&cloudformation.GetTemplateSummaryInput{
 		StackName: aws.String(name),
@@ -222,6 +365,18 @@ func Outputs(svc cfnInterface, name string) (map[string]string, error) {
 	return outputs, nil
 }
 
+func Resources(svc cfnInterface, name string) (resources []*cloudformation.StackResourceSummary, err error) {
+	params := &cloudformation.ListStackResourcesInput{
+		StackName: aws.String(name),
+	}
+
+	err = svc.ListStackResourcesPages(params, func(page *cloudformation.ListStackResourcesOutput, last bool) bool {
+		resources = append(resources, page.StackResourceSummaries...)
+		return true
+	})
+	return
+}
+
 func Status(svc cfnInterface, name string) (string, error) {
 	resp, err := svc.DescribeStacks(&cloudformation.DescribeStacksInput{
 		StackName: aws.String(name),
@@ -237,36 +392,31 @@ func Status(svc cfnInterface, name string) (string, error) {
 	return *resp.Stacks[0].StackStatus, nil
 }
 
+// GetError returns an *OperationError if a stack is in the final state of a failed operation
 func GetError(svc cfnInterface, name string) error {
 	status, err := Status(svc, name)
 	if err != nil {
 		return err
 	}
 
-	switch status {
-	case cloudformation.ResourceStatusUpdateFailed:
-		return fmt.Errorf("Stack failed to update")
-
-	case cloudformation.ResourceStatusCreateFailed:
-		return fmt.Errorf("Stack failed to create")
-
-	case cloudformation.StackStatusRollbackComplete:
-		return fmt.Errorf("Stack rollback succeeded")
-
-	case cloudformation.StackStatusRollbackFailed:
-		return fmt.Errorf("Stack rollback failed")
+	if _, failed := failedStatuses[status]; !failed {
+		return nil
+	}
 
-	case cloudformation.StackStatusUpdateRollbackComplete:
-		return fmt.Errorf("Stack update failed, rollback succeeded")
+	// the error is still useful without the failures if they can't be found
+	failures, _ := RootCauses(svc, name)
 
-	case cloudformation.StackStatusUpdateRollbackFailed:
-		return fmt.Errorf("Stack update failed, rollback failed")
+	return &OperationError{
+		StackName: name,
+		Status:    status,
+		Failures:  failures,
 	}
-
-	return nil
 }
 
 func IsNoUpdateErr(err error) bool {
+	if _, ok := err.(*NoUpdatesError); ok {
+		return true
+	}
 	if aerr, ok := err.(awserr.Error); ok {
 		if aerr.Code() == `ValidationError` &&
 			aerr.Message() == `No updates are to be performed.` {
@@ -275,3 +425,13 @@ func IsNoUpdateErr(err error) bool {
 	}
 	return false
 }
+
+func IsNotExistsErr(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		if aerr.Code() == `ValidationError` &&
+			strings.HasSuffix(aerr.Message(), `does not exist`) {
+			return true
+		}
+	}
+	return false
+}
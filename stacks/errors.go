@@ -0,0 +1,42 @@
+package stacks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// failedStatuses are the final stack statuses of failed operations and how they're described
+var failedStatuses = map[string]string{
+	cloudformation.StackStatusCreateFailed:           "Stack failed to create",
+	cloudformation.StackStatusRollbackComplete:       "Stack failed to create, rollback succeeded",
+	cloudformation.StackStatusRollbackFailed:         "Stack failed to create, rollback failed",
+	cloudformation.ResourceStatusUpdateFailed:        "Stack failed to update",
+	cloudformation.StackStatusUpdateRollbackComplete: "Stack update failed, rollback succeeded",
+	cloudformation.StackStatusUpdateRollbackFailed:   "Stack update failed, rollback failed",
+	cloudformation.StackStatusDeleteFailed:           "Stack failed to delete",
+}
+
+// OperationError is returned when a create, update or delete of a stack fails. Status is the final
+// status of the stack and Failures are the events of the resources that caused the failure.
+type OperationError struct {
+	StackName string
+	Status    string
+	Failures  []*cloudformation.StackEvent
+}
+
+func (e *OperationError) Error() string {
+	if msg, ok := failedStatuses[e.Status]; ok {
+		return msg
+	}
+	return fmt.Sprintf("Stack is %s", e.Status)
+}
+
+// NoUpdatesError is returned when an update wouldn't change a stack
+type NoUpdatesError struct {
+	StackName string
+}
+
+func (e *NoUpdatesError) Error() string {
+	return fmt.Sprintf("No updates to be performed, stack %s is up to date", e.StackName)
+}
@@ -0,0 +1,179 @@
+package stacks
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/lox/parfait/templates"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Export is a value exported by a stack for other stacks to import
+type Export struct {
+	Name      string
+	Value     string
+	StackName string
+	StackID   string
+}
+
+// Exports returns all the exports in the region, sorted by name
+func Exports(svc cfnInterface) ([]Export, error) {
+	exports := []Export{}
+	input := &cloudformation.ListExportsInput{}
+
+	for {
+		resp, err := svc.ListExports(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range resp.Exports {
+			exports = append(exports, Export{
+				Name:      aws.StringValue(e.Name),
+				Value:     aws.StringValue(e.Value),
+				StackName: StackNameFromID(aws.StringValue(e.ExportingStackId)),
+				StackID:   aws.StringValue(e.ExportingStackId),
+			})
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+
+	sort.Slice(exports, func(i, j int) bool {
+		return exports[i].Name < exports[j].Name
+	})
+
+	return exports, nil
+}
+
+// StackNameFromID returns the stack name from a stack id in the form
+// arn:aws:cloudformation:region:account:stack/name/uuid
+func StackNameFromID(id string) string {
+	parts := strings.Split(id, "/")
+	if len(parts) == 3 && strings.HasPrefix(parts[0], "arn:") {
+		return parts[1]
+	}
+	return id
+}
+
+// ExportConsumers finds the stacks that import each export by scanning the templates of all active
+// stacks for Fn::ImportValue. Names built with Fn::Sub are resolved with the stack's parameters and
+// pseudo parameters. Stacks whose templates can't be read or parsed are passed to warn and skipped.
+func ExportConsumers(svc cfnInterface, warn func(stackName string, err error)) (map[string][]string, error) {
+	stacks, err := FindAllActive(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	consumers := map[string][]string{}
+	for _, stack := range stacks {
+		resp, err := svc.GetTemplate(&cloudformation.GetTemplateInput{
+			StackName: stack.StackId,
+		})
+		if err != nil {
+			warn(*stack.StackName, err)
+			continue
+		}
+
+		vars := pseudoParams(*stack.StackId)
+		vars["AWS::StackName"] = *stack.StackName
+		for _, param := range stack.Parameters {
+			vars[*param.ParameterKey] = aws.StringValue(param.ParameterValue)
+		}
+
+		imports, err := findImports(aws.StringValue(resp.TemplateBody), vars)
+		if err != nil {
+			warn(*stack.StackName, err)
+			continue
+		}
+
+		for _, name := range imports {
+			consumers[name] = append(consumers[name], *stack.StackName)
+		}
+	}
+
+	for name := range consumers {
+		sort.Strings(consumers[name])
+	}
+
+	return consumers, nil
+}
+
+// pseudoParams returns the pseudo parameters that can be read from a stack id in the form
+// arn:partition:cloudformation:region:account:stack/name/uuid
+func pseudoParams(id string) map[string]string {
+	vars := map[string]string{
+		"AWS::StackId": id,
+	}
+	if parts := strings.SplitN(id, ":", 6); len(parts) == 6 && parts[0] == "arn" {
+		vars["AWS::Partition"] = parts[1]
+		vars["AWS::Region"] = parts[3]
+		vars["AWS::AccountId"] = parts[4]
+	}
+	return vars
+}
+
+// findImports returns the names imported with Fn::ImportValue in a JSON or YAML template body
+func findImports(body string, vars map[string]string) ([]string, error) {
+	tpl, err := templates.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	imports := []string{}
+
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if arg := templates.MapValue(n, "Fn::ImportValue"); arg != nil && len(n.Content) == 2 {
+			if name, ok := importName(arg, vars); ok && !seen[name] {
+				seen[name] = true
+				imports = append(imports, name)
+			}
+		}
+		for _, child := range n.Content {
+			walk(child)
+		}
+	}
+	walk(tpl.Root())
+
+	return imports, nil
+}
+
+var subVarPattern = regexp.MustCompile(`\$\{([^}!]+)\}`)
+
+// importName resolves the name passed to Fn::ImportValue, either a literal or an Fn::Sub
+func importName(n *yaml.Node, vars map[string]string) (string, bool) {
+	var sub *yaml.Node
+
+	switch {
+	case n.Kind == yaml.ScalarNode:
+		return n.Value, true
+	case n.Kind == yaml.MappingNode && len(n.Content) == 2 && n.Content[0].Value == "Fn::Sub":
+		sub = n.Content[1]
+		if sub.Kind == yaml.SequenceNode && len(sub.Content) > 0 {
+			sub = sub.Content[0]
+		}
+	}
+
+	if sub == nil || sub.Kind != yaml.ScalarNode {
+		return "", false
+	}
+
+	resolved := true
+	name := subVarPattern.ReplaceAllStringFunc(sub.Value, func(match string) string {
+		if v, ok := vars[match[2:len(match)-1]]; ok {
+			return v
+		}
+		resolved = false
+		return match
+	})
+
+	return name, resolved
+}
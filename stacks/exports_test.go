@@ -0,0 +1,133 @@
+package stacks
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// stackPagesFake returns stacks over several pages of DescribeStacks, and their templates
+type stackPagesFake struct {
+	cfnInterface
+	pages     [][]*cloudformation.Stack
+	templates map[string]string
+}
+
+func (f *stackPagesFake) DescribeStacksPages(input *cloudformation.DescribeStacksInput, fn func(*cloudformation.DescribeStacksOutput, bool) bool) error {
+	for i, page := range f.pages {
+		if !fn(&cloudformation.DescribeStacksOutput{Stacks: page}, i == len(f.pages)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func (f *stackPagesFake) GetTemplate(input *cloudformation.GetTemplateInput) (*cloudformation.GetTemplateOutput, error) {
+	body, ok := f.templates[*input.StackName]
+	if !ok {
+		return nil, errors.New("Access denied")
+	}
+	return &cloudformation.GetTemplateOutput{TemplateBody: aws.String(body)}, nil
+}
+
+func testStack(name string) *cloudformation.Stack {
+	return &cloudformation.Stack{
+		StackName:   aws.String(name),
+		StackId:     aws.String("arn:aws:cloudformation:us-east-1:123456789012:stack/" + name + "/abc"),
+		StackStatus: aws.String("CREATE_COMPLETE"),
+	}
+}
+
+func TestFindingImportsInTemplates(t *testing.T) {
+	vars := map[string]string{
+		"AWS::StackName": "my-app",
+		"NetworkStack":   "network",
+	}
+
+	yamlBody := `
+Resources:
+  Instance:
+    Type: AWS::EC2::Instance
+    Properties:
+      SubnetId: !ImportValue network-Subnet1
+      SecurityGroupIds:
+        - !ImportValue
+          Fn::Sub: "${NetworkStack}-SecurityGroup"
+        - Fn::ImportValue: !Sub "${Unknown}-SecurityGroup"
+`
+
+	imports, err := findImports(yamlBody, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"network-Subnet1", "network-SecurityGroup"}
+	if !reflect.DeepEqual(imports, expected) {
+		t.Fatalf("Expected %v, got %v", expected, imports)
+	}
+
+	jsonBody := `{"Resources": {"Instance": {"Type": "AWS::EC2::Instance", "Properties": {
+		"SubnetId": {"Fn::ImportValue": "network-Subnet1"},
+		"VpcId": {"Fn::ImportValue": {"Fn::Sub": "${NetworkStack}-VpcId"}}
+	}}}}`
+
+	imports, err = findImports(jsonBody, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = []string{"network-Subnet1", "network-VpcId"}
+	if !reflect.DeepEqual(imports, expected) {
+		t.Fatalf("Expected %v, got %v", expected, imports)
+	}
+}
+
+func TestStackNameFromID(t *testing.T) {
+	id := "arn:aws:cloudformation:us-east-1:123456789012:stack/network/1c2fa620-982a-11e3-aff7-50e2416294e0"
+	if name := StackNameFromID(id); name != "network" {
+		t.Fatalf("Expected network, got %q", name)
+	}
+}
+
+func TestFindingExportConsumers(t *testing.T) {
+	fake := &stackPagesFake{
+		pages: [][]*cloudformation.Stack{
+			{testStack("app"), testStack("denied")},
+			{testStack("broken"), testStack("worker")},
+		},
+		templates: map[string]string{
+			*testStack("app").StackId:    `{"Outputs": {"Subnet": {"Value": {"Fn::ImportValue": "network-Subnet"}}}}`,
+			*testStack("broken").StackId: "Resources: [",
+			*testStack("worker").StackId: `
+Outputs:
+  Vpc:
+    Value: !ImportValue
+      Fn::Sub: "${AWS::AccountId}-${AWS::Region}-Vpc"
+  Subnet:
+    Value: !ImportValue network-Subnet
+`,
+		},
+	}
+
+	skipped := []string{}
+	consumers, err := ExportConsumers(fake, func(stackName string, err error) {
+		skipped = append(skipped, stackName)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"network-Subnet":             {"app", "worker"},
+		"123456789012-us-east-1-Vpc": {"worker"},
+	}
+	if !reflect.DeepEqual(consumers, expected) {
+		t.Fatalf("Expected %v, got %v", expected, consumers)
+	}
+	if !reflect.DeepEqual(skipped, []string{"denied", "broken"}) {
+		t.Fatalf("Expected denied and broken to be skipped, got %v", skipped)
+	}
+}
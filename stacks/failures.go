@@ -0,0 +1,87 @@
+package stacks
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// operationStarts are the stack statuses that begin an operation, as opposed to a rollback
+var operationStarts = map[string]bool{
+	cloudformation.StackStatusCreateInProgress: true,
+	cloudformation.StackStatusUpdateInProgress: true,
+	cloudformation.StackStatusDeleteInProgress: true,
+}
+
+// RootCauses returns the failure events of the resources that failed during the most recent
+// operation on a stack, in the order they failed. Resources that were cancelled because something
+// else failed are left out, and failed nested stacks are replaced with the failures within them,
+// with their logical ids prefixed with the path to the nested stack like Network/Vpc.
+func RootCauses(svc cfnInterface, name string) ([]*cloudformation.StackEvent, error) {
+	return rootCauses(svc, name, nil)
+}
+
+func rootCauses(svc cfnInterface, name string, path []string) ([]*cloudformation.StackEvent, error) {
+	first := map[string]*cloudformation.StackEvent{}
+
+	params := &cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(name),
+	}
+
+	err := svc.DescribeStackEventsPages(params, func(page *cloudformation.DescribeStackEventsOutput, last bool) bool {
+		for _, event := range page.StackEvents {
+			isStack := aws.StringValue(event.LogicalResourceId) == aws.StringValue(event.StackName)
+
+			// events are newest first, so stop once we reach the start of the operation
+			if isStack && operationStarts[*event.ResourceStatus] {
+				return false
+			}
+
+			if !isStack && isRootCause(event) {
+				first[*event.LogicalResourceId] = event
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	failures := []*cloudformation.StackEvent{}
+	for _, event := range first {
+		if *event.ResourceType == "AWS::CloudFormation::Stack" && aws.StringValue(event.PhysicalResourceId) != "" {
+			nestedPath := append(append([]string{}, path...), *event.LogicalResourceId)
+			nested, err := rootCauses(svc, *event.PhysicalResourceId, nestedPath)
+			if err == nil && len(nested) > 0 {
+				failures = append(failures, nested...)
+				continue
+			}
+		}
+
+		if len(path) > 0 {
+			prefixed := *event
+			prefixed.LogicalResourceId = aws.String(strings.Join(append(path, *event.LogicalResourceId), "/"))
+			event = &prefixed
+		}
+		failures = append(failures, event)
+	}
+
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].Timestamp.Before(*failures[j].Timestamp)
+	})
+
+	return failures, nil
+}
+
+// isRootCause returns whether an event is a resource failing for its own reasons, rather than
+// being cancelled because another resource failed
+func isRootCause(event *cloudformation.StackEvent) bool {
+	if !strings.HasSuffix(*event.ResourceStatus, "_FAILED") {
+		return false
+	}
+
+	reason := strings.ToLower(aws.StringValue(event.ResourceStatusReason))
+	return !strings.Contains(reason, "cancelled")
+}
@@ -0,0 +1,75 @@
+package stacks
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+type stackEventsFake struct {
+	cfnInterface
+	events map[string][]*cloudformation.StackEvent
+}
+
+func (f *stackEventsFake) DescribeStackEventsPages(input *cloudformation.DescribeStackEventsInput, fn func(*cloudformation.DescribeStackEventsOutput, bool) bool) error {
+	fn(&cloudformation.DescribeStackEventsOutput{StackEvents: f.events[*input.StackName]}, true)
+	return nil
+}
+
+func TestFindingRootCauses(t *testing.T) {
+	start := time.Now()
+	event := func(stack, logicalID, resourceType, physicalID, status, reason string, secs int) *cloudformation.StackEvent {
+		return &cloudformation.StackEvent{
+			StackName:            aws.String(map[string]string{"app": "app", "network-arn": "app-Network"}[stack]),
+			LogicalResourceId:    aws.String(logicalID),
+			ResourceType:         aws.String(resourceType),
+			PhysicalResourceId:   aws.String(physicalID),
+			ResourceStatus:       aws.String(status),
+			ResourceStatusReason: aws.String(reason),
+			Timestamp:            aws.Time(start.Add(time.Duration(secs) * time.Second)),
+		}
+	}
+
+	fake := &stackEventsFake{events: map[string][]*cloudformation.StackEvent{
+		"app": {
+			event("app", "app", "AWS::CloudFormation::Stack", "app", "UPDATE_ROLLBACK_COMPLETE", "", 20),
+			event("app", "Queue", "AWS::SQS::Queue", "queue-1", "UPDATE_FAILED", "Resource update cancelled", 12),
+			event("app", "Network", "AWS::CloudFormation::Stack", "network-arn", "UPDATE_FAILED", "Embedded stack was not successfully updated", 11),
+			event("app", "Bucket", "AWS::S3::Bucket", "bucket-1", "UPDATE_FAILED", "Bucket name already exists", 5),
+			event("app", "Bucket", "AWS::S3::Bucket", "bucket-1", "UPDATE_FAILED", "Bucket name already exists", 4),
+			event("app", "app", "AWS::CloudFormation::Stack", "app", "UPDATE_IN_PROGRESS", "User Initiated", 1),
+			event("app", "Old", "AWS::SNS::Topic", "topic-1", "CREATE_FAILED", "From a previous create", 0),
+		},
+		"network-arn": {
+			event("network-arn", "Subnet", "AWS::EC2::Subnet", "", "UPDATE_FAILED", "Resource update cancelled", 9),
+			event("network-arn", "Vpc", "AWS::EC2::VPC", "vpc-1", "UPDATE_FAILED", "CIDR is invalid", 8),
+			event("network-arn", "app-Network", "AWS::CloudFormation::Stack", "network-arn", "UPDATE_IN_PROGRESS", "", 2),
+		},
+	}}
+
+	failures, err := RootCauses(fake, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := []string{}
+	for _, f := range failures {
+		actual = append(actual, *f.LogicalResourceId+": "+*f.ResourceStatusReason)
+	}
+
+	expected := []string{
+		"Bucket: Bucket name already exists",
+		"Network/Vpc: CIDR is invalid",
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v, got %v", expected, actual)
+	}
+
+	if !failures[0].Timestamp.Equal(start.Add(4 * time.Second)) {
+		t.Fatalf("Expected the first failure of Bucket, got %v", failures[0].Timestamp)
+	}
+}
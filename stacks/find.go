@@ -0,0 +1,85 @@
+package stacks
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// ResourceLocation is where a physical resource is defined
+type ResourceLocation struct {
+	StackName  string
+	LogicalID  string
+	PhysicalID string
+	Type       string
+	Status     string
+}
+
+// FindResource finds the stacks that own a physical resource. DescribeStackResources is tried
+// first, which doesn't support every type of resource, before searching all active stacks.
+func FindResource(svc cfnInterface, physicalID string) ([]ResourceLocation, error) {
+	resp, err := svc.DescribeStackResources(&cloudformation.DescribeStackResourcesInput{
+		PhysicalResourceId: aws.String(physicalID),
+	})
+	if err == nil && len(resp.StackResources) > 0 {
+		locations := []ResourceLocation{}
+		for _, r := range resp.StackResources {
+			if aws.StringValue(r.PhysicalResourceId) != physicalID {
+				continue
+			}
+			locations = append(locations, ResourceLocation{
+				StackName:  *r.StackName,
+				LogicalID:  *r.LogicalResourceId,
+				PhysicalID: physicalID,
+				Type:       *r.ResourceType,
+				Status:     *r.ResourceStatus,
+			})
+		}
+		if len(locations) > 0 {
+			return locations, nil
+		}
+	}
+
+	return searchResources(svc, physicalID)
+}
+
+func searchResources(svc cfnInterface, physicalID string) ([]ResourceLocation, error) {
+	stacks, err := FindAllActive(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := []ResourceLocation{}
+	for _, stack := range stacks {
+		resources, err := Resources(svc, *stack.StackId)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range resources {
+			if !matchesPhysicalID(aws.StringValue(r.PhysicalResourceId), physicalID) {
+				continue
+			}
+			locations = append(locations, ResourceLocation{
+				StackName:  *stack.StackName,
+				LogicalID:  *r.LogicalResourceId,
+				PhysicalID: *r.PhysicalResourceId,
+				Type:       *r.ResourceType,
+				Status:     *r.ResourceStatus,
+			})
+		}
+	}
+
+	return locations, nil
+}
+
+// matchesPhysicalID matches either an exact id or the last part of an arn or url
+func matchesPhysicalID(candidate, physicalID string) bool {
+	if candidate == "" {
+		return false
+	}
+	return candidate == physicalID ||
+		strings.HasSuffix(candidate, "/"+physicalID) ||
+		strings.HasSuffix(candidate, ":"+physicalID)
+}
@@ -0,0 +1,63 @@
+package stacks
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+type findResourceFake struct {
+	stackPagesFake
+	resources map[string][]*cloudformation.StackResourceSummary
+}
+
+func (f *findResourceFake) DescribeStackResources(input *cloudformation.DescribeStackResourcesInput) (*cloudformation.DescribeStackResourcesOutput, error) {
+	return nil, errors.New("ValidationError: Stack for " + *input.PhysicalResourceId + " does not exist")
+}
+
+func (f *findResourceFake) ListStackResourcesPages(input *cloudformation.ListStackResourcesInput, fn func(*cloudformation.ListStackResourcesOutput, bool) bool) error {
+	fn(&cloudformation.ListStackResourcesOutput{StackResourceSummaries: f.resources[*input.StackName]}, true)
+	return nil
+}
+
+func TestFindingResourcesAcrossStackPages(t *testing.T) {
+	resource := func(logicalID, physicalID string) *cloudformation.StackResourceSummary {
+		return &cloudformation.StackResourceSummary{
+			LogicalResourceId:  aws.String(logicalID),
+			PhysicalResourceId: aws.String(physicalID),
+			ResourceType:       aws.String("AWS::SQS::Queue"),
+			ResourceStatus:     aws.String("CREATE_COMPLETE"),
+		}
+	}
+
+	fake := &findResourceFake{
+		stackPagesFake: stackPagesFake{pages: [][]*cloudformation.Stack{
+			{testStack("app")},
+			{testStack("network")},
+			{testStack("worker")},
+		}},
+		resources: map[string][]*cloudformation.StackResourceSummary{
+			*testStack("app").StackId:    {resource("Queue", "https://sqs.us-east-1.amazonaws.com/123456789012/app-queue")},
+			*testStack("worker").StackId: {resource("Jobs", "https://sqs.us-east-1.amazonaws.com/123456789012/jobs")},
+		},
+	}
+
+	locations, err := FindResource(fake, "jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ResourceLocation{{
+		StackName:  "worker",
+		LogicalID:  "Jobs",
+		PhysicalID: "https://sqs.us-east-1.amazonaws.com/123456789012/jobs",
+		Type:       "AWS::SQS::Queue",
+		Status:     "CREATE_COMPLETE",
+	}}
+	if !reflect.DeepEqual(locations, expected) {
+		t.Fatalf("Expected %v, got %v", expected, locations)
+	}
+}
@@ -33,3 +33,43 @@ func FormatStackEvent(event *cloudformation.StackEvent) string {
 		descr,
 	)
 }
+
+func FormatChangeAction(action string) string {
+	switch action {
+	case cloudformation.ChangeActionAdd:
+		return color.GreenString(action)
+	case cloudformation.ChangeActionModify:
+		return color.YellowString(action)
+	case cloudformation.ChangeActionRemove:
+		return color.RedString(action)
+	}
+	return action
+}
+
+func FormatChange(change *cloudformation.Change) string {
+	rc := change.ResourceChange
+	if rc == nil {
+		return ""
+	}
+
+	replacement := "-"
+	if rc.Replacement != nil && *rc.Replacement != "" {
+		replacement = *rc.Replacement
+		if replacement == cloudformation.ReplacementTrue {
+			replacement = color.RedString(replacement)
+		}
+	}
+
+	scope := []string{}
+	for _, s := range rc.Scope {
+		scope = append(scope, *s)
+	}
+
+	return fmt.Sprintf("%s -> %s [%s] replacement=%s scope=%s",
+		FormatChangeAction(*rc.Action),
+		*rc.LogicalResourceId,
+		*rc.ResourceType,
+		replacement,
+		strings.Join(scope, ","),
+	)
+}
@@ -0,0 +1,159 @@
+package stacks
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// ListFilter selects stacks to list. Empty fields match every stack.
+type ListFilter struct {
+	// Statuses are stack statuses or globs like *_FAILED. Deleted stacks are only listed if they
+	// are matched by a status or IncludeDeleted is set.
+	Statuses       []string
+	IncludeDeleted bool
+	// Name is a glob, or a regular expression if it's wrapped in slashes like /^app-/
+	Name      string
+	Tags      map[string]string
+	OlderThan time.Time
+	NewerThan time.Time
+}
+
+// List returns summaries of the stacks that match a filter, including deleted stacks from the
+// last 90 days. Tags aren't part of a summary, so they are only described for stacks that match
+// the rest of the filter.
+func List(svc cfnInterface, filter ListFilter) ([]*cloudformation.StackSummary, error) {
+	matchName, err := nameMatcher(filter.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &cloudformation.ListStacksInput{}
+
+	// exact statuses can be filtered by the api, globs have to be matched here
+	if len(filter.Statuses) > 0 && !hasGlobs(filter.Statuses) {
+		input.StackStatusFilter = aws.StringSlice(filter.Statuses)
+	}
+
+	summaries := []*cloudformation.StackSummary{}
+	err = svc.ListStacksPages(input, func(page *cloudformation.ListStacksOutput, last bool) bool {
+		for _, s := range page.StackSummaries {
+			if filter.matches(s) && matchName(*s.StackName) {
+				summaries = append(summaries, s)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filter.Tags) == 0 {
+		return summaries, nil
+	}
+
+	tagged := []*cloudformation.StackSummary{}
+	for _, s := range summaries {
+		resp, err := svc.DescribeStacks(&cloudformation.DescribeStacksInput{
+			StackName: s.StackId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Stacks) > 0 && hasTags(resp.Stacks[0].Tags, filter.Tags) {
+			tagged = append(tagged, s)
+		}
+	}
+
+	return tagged, nil
+}
+
+// LastChanged returns when a stack was deleted, last updated or created
+func LastChanged(s *cloudformation.StackSummary) time.Time {
+	switch {
+	case s.DeletionTime != nil:
+		return *s.DeletionTime
+	case s.LastUpdatedTime != nil:
+		return *s.LastUpdatedTime
+	}
+	return *s.CreationTime
+}
+
+func (f ListFilter) matches(s *cloudformation.StackSummary) bool {
+	status := *s.StackStatus
+
+	if len(f.Statuses) > 0 {
+		if !matchesGlobs(f.Statuses, status) {
+			return false
+		}
+	} else if status == cloudformation.StackStatusDeleteComplete && !f.IncludeDeleted {
+		return false
+	}
+
+	changed := LastChanged(s)
+	if !f.OlderThan.IsZero() && !changed.Before(f.OlderThan) {
+		return false
+	}
+	if !f.NewerThan.IsZero() && !changed.After(f.NewerThan) {
+		return false
+	}
+
+	return true
+}
+
+func nameMatcher(pattern string) (func(string) bool, error) {
+	if pattern == "" {
+		return func(string) bool { return true }, nil
+	}
+
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return func(name string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}, nil
+}
+
+func hasGlobs(patterns []string) bool {
+	for _, p := range patterns {
+		if strings.ContainsAny(p, "*?[") {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesGlobs(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTags(tags []*cloudformation.Tag, want map[string]string) bool {
+	have := map[string]string{}
+	for _, t := range tags {
+		have[*t.Key] = *t.Value
+	}
+	for k, v := range want {
+		if actual, ok := have[k]; !ok || actual != v {
+			return false
+		}
+	}
+	return true
+}
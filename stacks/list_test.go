@@ -0,0 +1,89 @@
+package stacks
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+type listStacksFake struct {
+	cfnInterface
+	summaries []*cloudformation.StackSummary
+	tags      map[string]map[string]string
+}
+
+func (f *listStacksFake) ListStacksPages(input *cloudformation.ListStacksInput, fn func(*cloudformation.ListStacksOutput, bool) bool) error {
+	page := &cloudformation.ListStacksOutput{}
+	for _, s := range f.summaries {
+		if len(input.StackStatusFilter) == 0 || matchesGlobs(aws.StringValueSlice(input.StackStatusFilter), *s.StackStatus) {
+			page.StackSummaries = append(page.StackSummaries, s)
+		}
+	}
+	fn(page, true)
+	return nil
+}
+
+func (f *listStacksFake) DescribeStacks(input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+	stack := &cloudformation.Stack{StackId: input.StackName}
+	for k, v := range f.tags[*input.StackName] {
+		stack.Tags = append(stack.Tags, &cloudformation.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return &cloudformation.DescribeStacksOutput{Stacks: []*cloudformation.Stack{stack}}, nil
+}
+
+func summary(name, status string, created time.Time) *cloudformation.StackSummary {
+	return &cloudformation.StackSummary{
+		StackId:      aws.String("id/" + name),
+		StackName:    aws.String(name),
+		StackStatus:  aws.String(status),
+		CreationTime: aws.Time(created),
+	}
+}
+
+func TestListingStacks(t *testing.T) {
+	now := time.Now()
+
+	fake := &listStacksFake{
+		summaries: []*cloudformation.StackSummary{
+			summary("app-prod", "UPDATE_COMPLETE", now.Add(-time.Hour)),
+			summary("app-old", "DELETE_COMPLETE", now.Add(-48*time.Hour)),
+			summary("app-dev", "ROLLBACK_FAILED", now.Add(-72*time.Hour)),
+			summary("network", "CREATE_COMPLETE", now.Add(-96*time.Hour)),
+		},
+		tags: map[string]map[string]string{
+			"id/app-prod": {"env": "prod"},
+			"id/app-dev":  {"env": "dev"},
+		},
+	}
+
+	for _, tc := range []struct {
+		filter   ListFilter
+		expected []string
+	}{
+		{ListFilter{}, []string{"app-prod", "app-dev", "network"}},
+		{ListFilter{IncludeDeleted: true}, []string{"app-prod", "app-old", "app-dev", "network"}},
+		{ListFilter{Statuses: []string{"DELETE_COMPLETE"}}, []string{"app-old"}},
+		{ListFilter{Statuses: []string{"*_FAILED", "CREATE_*"}}, []string{"app-dev", "network"}},
+		{ListFilter{Name: "app-*"}, []string{"app-prod", "app-dev"}},
+		{ListFilter{Name: "/^(net|app-p)/"}, []string{"app-prod", "network"}},
+		{ListFilter{Tags: map[string]string{"env": "dev"}}, []string{"app-dev"}},
+		{ListFilter{OlderThan: now.Add(-24 * time.Hour), NewerThan: now.Add(-80 * time.Hour)}, []string{"app-dev"}},
+	} {
+		summaries, err := List(fake, tc.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names := []string{}
+		for _, s := range summaries {
+			names = append(names, *s.StackName)
+		}
+
+		if !reflect.DeepEqual(names, tc.expected) {
+			t.Errorf("Expected %v for %+v, got %v", tc.expected, tc.filter, names)
+		}
+	}
+}
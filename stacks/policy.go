@@ -0,0 +1,41 @@
+package stacks
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// policySource returns either a policy body or url for the api, neither is set if both are empty
+func policySource(body, url string) (*string, *string) {
+	switch {
+	case url != "":
+		return nil, aws.String(url)
+	case body != "":
+		return aws.String(body), nil
+	}
+	return nil, nil
+}
+
+// GetPolicy returns the stack policy of a stack, or an empty string if it has none
+func GetPolicy(svc cfnInterface, name string) (string, error) {
+	resp, err := svc.GetStackPolicy(&cloudformation.GetStackPolicyInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(resp.StackPolicyBody), nil
+}
+
+// SetPolicy sets the stack policy of a stack from either a body or a url
+func SetPolicy(svc cfnInterface, name string, body, url string) error {
+	policyBody, policyURL := policySource(body, url)
+
+	_, err := svc.SetStackPolicy(&cloudformation.SetStackPolicyInput{
+		StackName:       aws.String(name),
+		StackPolicyBody: policyBody,
+		StackPolicyURL:  policyURL,
+	})
+	return err
+}
@@ -2,6 +2,8 @@ package poller
 
 import (
 	"errors"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -9,6 +11,9 @@ import (
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 )
 
+// pollInterval is how long to wait between polling for events
+var pollInterval = 2 * time.Second
+
 type cfnInterface interface {
 	DescribeStackEventsPages(*cfn.DescribeStackEventsInput, func(*cfn.DescribeStackEventsOutput, bool) bool) error
 }
@@ -16,17 +21,29 @@ type cfnInterface interface {
 type Poller struct {
 	StackName string
 	awsApi    cfnInterface
+
+	// path is the logical ids of the nested stacks that this poller's stack is within
+	path []string
+
+	// mu is shared with nested pollers so that events are handled one at a time
+	mu *sync.Mutex
 }
 
 func NewPoller(api cfnInterface, stackName string) *Poller {
 	return &Poller{
 		StackName: stackName,
 		awsApi:    api,
+		mu:        &sync.Mutex{},
 	}
 }
 
+// Poll calls f with each new event until the end condition is met. Events of nested stacks are
+// polled as well whilst they are in progress, and are passed to f with their LogicalResourceId
+// prefixed with the path to the nested stack, like Network/Vpc.
 func (p *Poller) Poll(condition EndCondition, f func(e *cfn.StackEvent)) error {
 	lastSeen := time.Time{}
+	nested := newNestedPollers(p, f)
+	defer nested.stopAll()
 
 	for {
 		events, err := p.getEvents(lastSeen)
@@ -36,7 +53,8 @@ func (p *Poller) Poll(condition EndCondition, f func(e *cfn.StackEvent)) error {
 
 		for i := len(events) - 1; i >= 0; i-- {
 			if events[i].Timestamp.After(lastSeen) {
-				f(events[i])
+				p.handle(f, events[i])
+				nested.track(events[i])
 				lastSeen = *events[i].Timestamp
 			}
 		}
@@ -51,12 +69,124 @@ func (p *Poller) Poll(condition EndCondition, f func(e *cfn.StackEvent)) error {
 			}
 		}
 
-		time.Sleep(2 * time.Second)
+		time.Sleep(pollInterval)
 	}
 
 	return nil
 }
 
+// pollNested polls the events of a nested stack until it's told to stop, polling one last time
+// so that no events are missed
+func (p *Poller) pollNested(after time.Time, stop <-chan struct{}, f func(e *cfn.StackEvent)) {
+	lastSeen := after
+	nested := newNestedPollers(p, f)
+	defer nested.stopAll()
+
+	for stopping := false; ; {
+		// errors like throttling are retried on the next poll
+		events, _ := p.getEvents(lastSeen)
+
+		for i := len(events) - 1; i >= 0; i-- {
+			if events[i].Timestamp.After(lastSeen) {
+				p.handle(f, events[i])
+				nested.track(events[i])
+				lastSeen = *events[i].Timestamp
+			}
+		}
+
+		if stopping {
+			return
+		}
+
+		select {
+		case <-stop:
+			stopping = true
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// handle passes an event to f, prefixing the logical ids of events in nested stacks with the path
+// to the stack. Events for a nested stack itself are skipped, as its parent has the same events.
+func (p *Poller) handle(f func(e *cfn.StackEvent), event *cfn.StackEvent) {
+	if len(p.path) > 0 {
+		if aws.StringValue(event.LogicalResourceId) == aws.StringValue(event.StackName) {
+			return
+		}
+		prefixed := *event
+		prefixed.LogicalResourceId = aws.String(strings.Join(append(p.path, *event.LogicalResourceId), "/"))
+		event = &prefixed
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f(event)
+}
+
+// nestedPollers starts and stops pollers for the nested stacks of a stack based on its events
+type nestedPollers struct {
+	parent  *Poller
+	f       func(e *cfn.StackEvent)
+	running map[string]chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newNestedPollers(parent *Poller, f func(e *cfn.StackEvent)) *nestedPollers {
+	return &nestedPollers{
+		parent:  parent,
+		f:       f,
+		running: map[string]chan struct{}{},
+	}
+}
+
+// track starts polling a nested stack when its resource is in progress, and stops when it's not
+func (n *nestedPollers) track(event *cfn.StackEvent) {
+	if aws.StringValue(event.ResourceType) != "AWS::CloudFormation::Stack" ||
+		aws.StringValue(event.PhysicalResourceId) == "" ||
+		aws.StringValue(event.PhysicalResourceId) == aws.StringValue(event.StackId) {
+		return
+	}
+
+	id := *event.PhysicalResourceId
+	stop, running := n.running[id]
+	inProgress := strings.HasSuffix(aws.StringValue(event.ResourceStatus), "_IN_PROGRESS")
+
+	switch {
+	case inProgress && !running:
+		stop = make(chan struct{})
+		n.running[id] = stop
+
+		child := &Poller{
+			StackName: id,
+			awsApi:    n.parent.awsApi,
+			path:      append(append([]string{}, n.parent.path...), *event.LogicalResourceId),
+			mu:        n.parent.mu,
+		}
+
+		// events of the nested stack can be in the same second as the event of its resource
+		after := event.Timestamp.Add(-time.Second)
+
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			child.pollNested(after, stop, n.f)
+		}()
+
+	case !inProgress && running:
+		close(stop)
+		delete(n.running, id)
+	}
+}
+
+// stopAll stops any nested pollers that are still running and waits for them to finish
+func (n *nestedPollers) stopAll() {
+	for id, stop := range n.running {
+		close(stop)
+		delete(n.running, id)
+	}
+	n.wg.Wait()
+}
+
 // getEvents returns all events after a given time in reverse chronological order
 func (p *Poller) getEvents(after time.Time) (events []*cfn.StackEvent, err error) {
 	params := &cfn.DescribeStackEventsInput{
@@ -0,0 +1,96 @@
+package poller
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+const childArn = "arn:aws:cloudformation:us-east-1:123:stack/app-Network-ABC/1"
+
+// nestedStackFake returns the events of a parent stack in two polls, and a nested stack whose
+// resources change between them
+type nestedStackFake struct {
+	sync.Mutex
+	start time.Time
+	polls map[string]int
+}
+
+func (f *nestedStackFake) event(stack, logicalID, resourceType, physicalID, status string, secs int) *cfn.StackEvent {
+	return &cfn.StackEvent{
+		StackId:            aws.String(stack),
+		StackName:          aws.String(map[string]string{childArn: "app-Network-ABC"}[stack]),
+		LogicalResourceId:  aws.String(logicalID),
+		ResourceType:       aws.String(resourceType),
+		PhysicalResourceId: aws.String(physicalID),
+		ResourceStatus:     aws.String(status),
+		Timestamp:          aws.Time(f.start.Add(time.Duration(secs) * time.Second)),
+	}
+}
+
+func (f *nestedStackFake) DescribeStackEventsPages(input *cfn.DescribeStackEventsInput, fn func(*cfn.DescribeStackEventsOutput, bool) bool) error {
+	f.Lock()
+	f.polls[*input.StackName]++
+	polls := f.polls[*input.StackName]
+	f.Unlock()
+
+	var events []*cfn.StackEvent
+
+	switch *input.StackName {
+	case "app":
+		events = []*cfn.StackEvent{
+			f.event("app", "Network", "AWS::CloudFormation::Stack", childArn, "UPDATE_IN_PROGRESS", 1),
+			f.event("app", "app", "AWS::CloudFormation::Stack", "app", "UPDATE_IN_PROGRESS", 0),
+		}
+		if polls > 1 {
+			events = append([]*cfn.StackEvent{
+				f.event("app", "app", "AWS::CloudFormation::Stack", "app", "UPDATE_COMPLETE", 6),
+				f.event("app", "Network", "AWS::CloudFormation::Stack", childArn, "UPDATE_COMPLETE", 5),
+			}, events...)
+		}
+		events[len(events)-1].ResourceStatusReason = aws.String("User Initiated")
+
+	case childArn:
+		events = []*cfn.StackEvent{
+			f.event(childArn, "app-Network-ABC", "AWS::CloudFormation::Stack", childArn, "UPDATE_COMPLETE", 4),
+			f.event(childArn, "Vpc", "AWS::EC2::VPC", "vpc-1", "UPDATE_COMPLETE", 3),
+			f.event(childArn, "app-Network-ABC", "AWS::CloudFormation::Stack", childArn, "UPDATE_IN_PROGRESS", 2),
+		}
+	}
+
+	fn(&cfn.DescribeStackEventsOutput{StackEvents: events}, true)
+	return nil
+}
+
+func TestPollingNestedStacks(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	defer func() { pollInterval = 2 * time.Second }()
+
+	fake := &nestedStackFake{start: time.Now(), polls: map[string]int{}}
+
+	seen := []string{}
+	err := UntilCreatedOrUpdated(fake, "app", func(e *cfn.StackEvent) {
+		seen = append(seen, *e.LogicalResourceId+" "+*e.ResourceStatus)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(seen)
+	expected := []string{
+		"Network UPDATE_COMPLETE",
+		"Network UPDATE_IN_PROGRESS",
+		"Network/Vpc UPDATE_COMPLETE",
+		"app UPDATE_COMPLETE",
+		"app UPDATE_IN_PROGRESS",
+	}
+
+	if !reflect.DeepEqual(seen, expected) {
+		t.Fatalf("Expected %v, got %v", expected, seen)
+	}
+}
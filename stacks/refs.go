@@ -0,0 +1,125 @@
+package stacks
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var outputRefPattern = regexp.MustCompile(`\{\{\s*stack:([^}\s]+)\s*\}\}`)
+
+// OutputRef is a reference to an output of another stack in a parameter value, in the form
+// {{stack:name.OutputKey}}, optionally qualified as {{stack:name.OutputKey?region=x&profile=y}}
+type OutputRef struct {
+	StackName string
+	OutputKey string
+	Region    string
+	Profile   string
+}
+
+func (r OutputRef) String() string {
+	s := r.StackName + "." + r.OutputKey
+	qualifiers := []string{}
+	if r.Region != "" {
+		qualifiers = append(qualifiers, "region="+r.Region)
+	}
+	if r.Profile != "" {
+		qualifiers = append(qualifiers, "profile="+r.Profile)
+	}
+	if len(qualifiers) > 0 {
+		s += "?" + strings.Join(qualifiers, "&")
+	}
+	return s
+}
+
+func parseOutputRef(ref string) (OutputRef, error) {
+	var query string
+	if idx := strings.Index(ref, "?"); idx != -1 {
+		ref, query = ref[:idx], ref[idx+1:]
+	}
+
+	parts := strings.SplitN(ref, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return OutputRef{}, fmt.Errorf("Invalid stack output reference %q, expected stack.OutputKey", ref)
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return OutputRef{}, fmt.Errorf("Invalid qualifiers in stack output reference %q: %v", ref, err)
+	}
+
+	for k := range values {
+		if k != "region" && k != "profile" {
+			return OutputRef{}, fmt.Errorf("Unknown qualifier %q in stack output reference %q", k, ref)
+		}
+	}
+
+	return OutputRef{
+		StackName: parts[0],
+		OutputKey: parts[1],
+		Region:    values.Get("region"),
+		Profile:   values.Get("profile"),
+	}, nil
+}
+
+// ParamResolution records where a resolved parameter value came from
+type ParamResolution struct {
+	Param string
+	Ref   OutputRef
+	Value string
+}
+
+// ResolveParams replaces any stack output references in parameter values using the outputs
+// returned by the lookup function, which is called once per referenced stack
+func ResolveParams(params map[string]string, lookup func(ref OutputRef) (map[string]string, error)) (map[string]string, []ParamResolution, error) {
+	resolved := map[string]string{}
+	resolutions := []ParamResolution{}
+	cache := map[string]map[string]string{}
+
+	for k, v := range params {
+		var resolveErr error
+
+		resolved[k] = outputRefPattern.ReplaceAllStringFunc(v, func(match string) string {
+			if resolveErr != nil {
+				return match
+			}
+
+			ref, err := parseOutputRef(outputRefPattern.FindStringSubmatch(match)[1])
+			if err != nil {
+				resolveErr = err
+				return match
+			}
+
+			cacheKey := fmt.Sprintf("%s/%s/%s", ref.Profile, ref.Region, ref.StackName)
+			outputs, ok := cache[cacheKey]
+			if !ok {
+				if outputs, err = lookup(ref); err != nil {
+					resolveErr = fmt.Errorf("Failed to read outputs of stack %s for %s: %v", ref.StackName, k, err)
+					return match
+				}
+				cache[cacheKey] = outputs
+			}
+
+			value, ok := outputs[ref.OutputKey]
+			if !ok {
+				resolveErr = fmt.Errorf("Stack %s has no output %s for %s", ref.StackName, ref.OutputKey, k)
+				return match
+			}
+
+			resolutions = append(resolutions, ParamResolution{Param: k, Ref: ref, Value: value})
+			return value
+		})
+
+		if resolveErr != nil {
+			return nil, nil, resolveErr
+		}
+	}
+
+	sort.SliceStable(resolutions, func(i, j int) bool {
+		return resolutions[i].Param < resolutions[j].Param
+	})
+
+	return resolved, resolutions, nil
+}
@@ -0,0 +1,68 @@
+package stacks
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestResolvingParamsFromStackOutputs(t *testing.T) {
+	lookups := 0
+	lookup := func(ref OutputRef) (map[string]string, error) {
+		lookups++
+		switch ref.StackName {
+		case "network":
+			return map[string]string{
+				"VpcId":   "vpc-1234",
+				"Subnet1": "subnet-1",
+				"Subnet2": "subnet-2",
+			}, nil
+		}
+		return nil, fmt.Errorf("Stack %s does not exist", ref.StackName)
+	}
+
+	resolved, resolutions, err := ResolveParams(map[string]string{
+		"VpcId":   "{{stack:network.VpcId}}",
+		"Subnets": "{{stack:network.Subnet1}},{{ stack:network.Subnet2 }}",
+		"KeyName": "llamas",
+	}, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"VpcId":   "vpc-1234",
+		"Subnets": "subnet-1,subnet-2",
+		"KeyName": "llamas",
+	}
+
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Fatalf("Expected %#v, got %#v", expected, resolved)
+	}
+
+	if len(resolutions) != 3 {
+		t.Fatalf("Expected 3 resolutions, got %d", len(resolutions))
+	}
+
+	if lookups != 1 {
+		t.Fatalf("Expected outputs to be looked up once, got %d", lookups)
+	}
+}
+
+func TestParsingQualifiedOutputRefs(t *testing.T) {
+	ref, err := parseOutputRef("network.VpcId?region=us-east-1&profile=prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := OutputRef{StackName: "network", OutputKey: "VpcId", Region: "us-east-1", Profile: "prod"}
+	if ref != expected {
+		t.Fatalf("Expected %#v, got %#v", expected, ref)
+	}
+
+	for _, invalid := range []string{"network", "network.", ".VpcId", "network.VpcId?zone=a"} {
+		if _, err := parseOutputRef(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
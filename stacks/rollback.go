@@ -0,0 +1,96 @@
+package stacks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// ContinueUpdateRollback continues rolling back a stack in UPDATE_ROLLBACK_FAILED, optionally
+// skipping resources that can't be rolled back
+func ContinueUpdateRollback(svc cfnInterface, name string, skipResources []string) error {
+	input := &cloudformation.ContinueUpdateRollbackInput{
+		StackName: aws.String(name),
+	}
+	if len(skipResources) > 0 {
+		input.ResourcesToSkip = aws.StringSlice(skipResources)
+	}
+
+	_, err := svc.ContinueUpdateRollback(input)
+	return err
+}
+
+// RollbackFailures returns the latest failure event of each resource that failed during the most
+// recent update rollback, in the order they failed
+func RollbackFailures(svc cfnInterface, name string) ([]*cloudformation.StackEvent, error) {
+	failures := []*cloudformation.StackEvent{}
+	seen := map[string]bool{}
+
+	params := &cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(name),
+	}
+
+	err := svc.DescribeStackEventsPages(params, func(page *cloudformation.DescribeStackEventsOutput, last bool) bool {
+		for _, event := range page.StackEvents {
+			// name may be a stack id, so the stack's own events are found by their stack name
+			isStack := aws.StringValue(event.LogicalResourceId) == aws.StringValue(event.StackName)
+
+			// stop once we reach the start of the rollback
+			if isStack && *event.ResourceStatus == cloudformation.StackStatusUpdateRollbackInProgress {
+				return false
+			}
+
+			if strings.HasSuffix(*event.ResourceStatus, "_FAILED") &&
+				!isStack && !seen[*event.LogicalResourceId] {
+				seen[*event.LogicalResourceId] = true
+				failures = append([]*cloudformation.StackEvent{event}, failures...)
+			}
+		}
+		return true
+	})
+
+	return failures, err
+}
+
+// rollbackPollInterval is how long to wait between checks for a rollback to start
+var rollbackPollInterval = 2 * time.Second
+
+// rollbackStartTimeout is how long to wait for a continued rollback to start
+var rollbackStartTimeout = 5 * time.Minute
+
+// WaitForRollback waits for an update rollback of a stack to start after a given time, so that
+// watching it doesn't end at the failure of the previous rollback
+func WaitForRollback(svc cfnInterface, name string, after time.Time) error {
+	params := &cloudformation.DescribeStackEventsInput{
+		StackName: aws.String(name),
+	}
+
+	for deadline := time.Now().Add(rollbackStartTimeout); ; {
+		started := false
+
+		err := svc.DescribeStackEventsPages(params, func(page *cloudformation.DescribeStackEventsOutput, last bool) bool {
+			for _, event := range page.StackEvents {
+				if !event.Timestamp.After(after) {
+					return false
+				}
+				if aws.StringValue(event.LogicalResourceId) == aws.StringValue(event.StackName) &&
+					*event.ResourceStatus == cloudformation.StackStatusUpdateRollbackInProgress {
+					started = true
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil || started {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timed out waiting for the rollback of %s to start", name)
+		}
+		time.Sleep(rollbackPollInterval)
+	}
+}
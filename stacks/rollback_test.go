@@ -0,0 +1,93 @@
+package stacks
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+const appStackID = "arn:aws:cloudformation:us-east-1:123456789012:stack/app/abc"
+
+func rollbackEvent(logicalID, status string, t time.Time) *cloudformation.StackEvent {
+	return &cloudformation.StackEvent{
+		StackName:         aws.String("app"),
+		LogicalResourceId: aws.String(logicalID),
+		ResourceType:      aws.String("AWS::SQS::Queue"),
+		ResourceStatus:    aws.String(status),
+		Timestamp:         aws.Time(t),
+	}
+}
+
+func TestFindingRollbackFailuresByStackID(t *testing.T) {
+	start := time.Now()
+	fake := &stackEventsFake{events: map[string][]*cloudformation.StackEvent{
+		appStackID: {
+			rollbackEvent("app", "UPDATE_ROLLBACK_FAILED", start.Add(4*time.Second)),
+			rollbackEvent("Queue", "UPDATE_FAILED", start.Add(3*time.Second)),
+			rollbackEvent("app", "UPDATE_ROLLBACK_IN_PROGRESS", start.Add(2*time.Second)),
+			rollbackEvent("Topic", "UPDATE_FAILED", start.Add(time.Second)),
+		},
+	}}
+
+	failures, err := RollbackFailures(fake, appStackID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{}
+	for _, event := range failures {
+		ids = append(ids, *event.LogicalResourceId)
+	}
+	if !reflect.DeepEqual(ids, []string{"Queue"}) {
+		t.Fatalf("Expected only Queue to have failed during rollback, got %v", ids)
+	}
+}
+
+type rollbackFake struct {
+	cfnInterface
+	polls  [][]*cloudformation.StackEvent
+	called int
+}
+
+func (f *rollbackFake) DescribeStackEventsPages(input *cloudformation.DescribeStackEventsInput, fn func(*cloudformation.DescribeStackEventsOutput, bool) bool) error {
+	poll := f.polls[f.called]
+	if f.called < len(f.polls)-1 {
+		f.called++
+	}
+	fn(&cloudformation.DescribeStackEventsOutput{StackEvents: poll}, true)
+	return nil
+}
+
+func TestWaitingForRollback(t *testing.T) {
+	defer func(interval, timeout time.Duration) {
+		rollbackPollInterval, rollbackStartTimeout = interval, timeout
+	}(rollbackPollInterval, rollbackStartTimeout)
+	rollbackPollInterval = 0
+
+	start := time.Now()
+	failed := []*cloudformation.StackEvent{
+		rollbackEvent("app", "UPDATE_ROLLBACK_FAILED", start.Add(-time.Second)),
+		rollbackEvent("app", "UPDATE_ROLLBACK_IN_PROGRESS", start.Add(-2*time.Second)),
+	}
+	started := append([]*cloudformation.StackEvent{
+		rollbackEvent("app", "UPDATE_ROLLBACK_IN_PROGRESS", start.Add(time.Second)),
+	}, failed...)
+
+	fake := &rollbackFake{polls: [][]*cloudformation.StackEvent{failed, failed, started}}
+	if err := WaitForRollback(fake, appStackID, start); err != nil {
+		t.Fatal(err)
+	}
+	if fake.called != 2 {
+		t.Fatalf("Expected to poll until the rollback started, polled %d times", fake.called+1)
+	}
+
+	// a rollback that never starts shouldn't wait forever
+	rollbackStartTimeout = 0
+	fake = &rollbackFake{polls: [][]*cloudformation.StackEvent{failed}}
+	if err := WaitForRollback(fake, appStackID, start); err == nil {
+		t.Fatal("Expected an error when the rollback doesn't start")
+	}
+}
@@ -0,0 +1,109 @@
+package stacks
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+func buildTags(tags map[string]string) []*cloudformation.Tag {
+	keys := []string{}
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tagsSlice := []*cloudformation.Tag{}
+	for _, k := range keys {
+		tagsSlice = append(tagsSlice, &cloudformation.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(tags[k]),
+		})
+	}
+	return tagsSlice
+}
+
+// Tags returns the current tags on a stack
+func Tags(svc cfnInterface, name string) (map[string]string, error) {
+	resp, err := svc.DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Stacks) != 1 {
+		return nil, fmt.Errorf("Expected 1 stack, got %d", len(resp.Stacks))
+	}
+
+	tags := map[string]string{}
+	for _, tag := range resp.Stacks[0].Tags {
+		tags[*tag.Key] = *tag.Value
+	}
+
+	return tags, nil
+}
+
+// updateTags merges tag changes into a stack's existing tags. If there are no changes nil is
+// returned, which leaves the existing tags in place on update.
+func updateTags(svc cfnInterface, name string, tags map[string]string, remove []string) ([]*cloudformation.Tag, error) {
+	if len(tags) == 0 && len(remove) == 0 {
+		return nil, nil
+	}
+
+	existing, err := Tags(svc, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range tags {
+		existing[k] = v
+	}
+
+	for _, k := range remove {
+		delete(existing, k)
+	}
+
+	return buildTags(existing), nil
+}
+
+// UpdateTags changes only the tags on a stack, using the previous template and parameters
+func UpdateTags(svc cfnInterface, name string, tags map[string]string, remove []string) error {
+	resp, err := svc.DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Stacks) != 1 {
+		return fmt.Errorf("Expected 1 stack, got %d", len(resp.Stacks))
+	}
+
+	paramsSlice := []*cloudformation.Parameter{}
+	for _, param := range resp.Stacks[0].Parameters {
+		paramsSlice = append(paramsSlice, &cloudformation.Parameter{
+			ParameterKey:     param.ParameterKey,
+			UsePreviousValue: aws.Bool(true),
+		})
+	}
+
+	tagsSlice, err := updateTags(svc, name, tags, remove)
+	if err != nil {
+		return err
+	}
+
+	_, err = svc.UpdateStack(&cloudformation.UpdateStackInput{
+		StackName:           aws.String(name),
+		Capabilities:        resp.Stacks[0].Capabilities,
+		Parameters:          paramsSlice,
+		Tags:                tagsSlice,
+		UsePreviousTemplate: aws.Bool(true),
+	})
+	if IsNoUpdateErr(err) {
+		return &NoUpdatesError{StackName: name}
+	}
+	return err
+}
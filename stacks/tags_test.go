@@ -0,0 +1,77 @@
+package stacks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+type tagsFake struct {
+	cfnInterface
+	tags map[string]string
+}
+
+func (f *tagsFake) DescribeStacks(input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+	stack := &cloudformation.Stack{StackName: input.StackName}
+	for k, v := range f.tags {
+		stack.Tags = append(stack.Tags, &cloudformation.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return &cloudformation.DescribeStacksOutput{Stacks: []*cloudformation.Stack{stack}}, nil
+}
+
+func TestUpdatingTags(t *testing.T) {
+	existing := map[string]string{"env": "prod", "team": "infra"}
+
+	for _, tc := range []struct {
+		name     string
+		tags     map[string]string
+		remove   []string
+		expected []*cloudformation.Tag
+	}{
+		{
+			name:     "no changes keeps existing tags",
+			expected: nil,
+		},
+		{
+			name:     "new tags are merged with existing",
+			tags:     map[string]string{"owner": "lox"},
+			expected: buildTags(map[string]string{"env": "prod", "team": "infra", "owner": "lox"}),
+		},
+		{
+			name:     "existing tags are overridden",
+			tags:     map[string]string{"env": "staging"},
+			expected: buildTags(map[string]string{"env": "staging", "team": "infra"}),
+		},
+		{
+			name:     "tags are removed",
+			remove:   []string{"team", "missing"},
+			expected: buildTags(map[string]string{"env": "prod"}),
+		},
+		{
+			name:     "removals win over tags being set",
+			tags:     map[string]string{"team": "platform", "owner": "lox"},
+			remove:   []string{"team"},
+			expected: buildTags(map[string]string{"env": "prod", "owner": "lox"}),
+		},
+		{
+			name:     "removing every tag sends an empty list",
+			remove:   []string{"env", "team"},
+			expected: []*cloudformation.Tag{},
+		},
+	} {
+		fake := &tagsFake{tags: map[string]string{}}
+		for k, v := range existing {
+			fake.tags[k] = v
+		}
+
+		tags, err := updateTags(fake, "app", tc.tags, tc.remove)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tags, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, tags)
+		}
+	}
+}
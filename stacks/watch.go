@@ -2,18 +2,17 @@ package stacks
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/lox/parfait/stacks/poller"
 )
 
+// Watch polls a stack's events until a create or update finishes and prints its outputs. If the
+// operation failed an *OperationError is returned.
 func Watch(cfn cfnInterface, stackName string, f func(event *cloudformation.StackEvent)) error {
-	err := poller.UntilCreatedOrUpdated(cfn, stackName, f)
-	if err != nil {
-		return err
-	}
-
-	if err = GetError(cfn, stackName); err != nil {
+	if err := WaitForOperation(cfn, stackName, f); err != nil {
 		return err
 	}
 
@@ -22,11 +21,48 @@ func Watch(cfn cfnInterface, stackName string, f func(event *cloudformation.Stac
 		return err
 	}
 
+	keys := []string{}
+	for k := range outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	fmt.Println()
 	fmt.Printf("%-20s %-80s\n", "KEY", "VALUE")
-	for k, v := range outputs {
-		fmt.Printf("%-20s %-80s\n", k, v)
+	for _, k := range keys {
+		fmt.Printf("%-20s %-80s\n", k, outputs[k])
 	}
 
 	return nil
 }
+
+// WaitForOperation polls a stack's events until a create or update finishes, returning an
+// *OperationError if it failed
+func WaitForOperation(cfn cfnInterface, stackName string, f func(event *cloudformation.StackEvent)) error {
+	err := poller.UntilCreatedOrUpdated(cfn, stackName, f)
+
+	// the final status of the stack is a better description of a failure than the last event
+	if _, isAPIErr := err.(awserr.Error); isAPIErr {
+		return err
+	}
+
+	return GetError(cfn, stackName)
+}
+
+// WatchDelete polls a stack's events until it's deleted, returning an *OperationError if the
+// delete failed
+func WatchDelete(cfn cfnInterface, stackName string, f func(event *cloudformation.StackEvent)) error {
+	err := poller.UntilDeleted(cfn, stackName, f)
+	if err == nil {
+		return nil
+	}
+
+	if _, isAPIErr := err.(awserr.Error); isAPIErr {
+		return err
+	}
+
+	if opErr := GetError(cfn, stackName); opErr != nil {
+		return opErr
+	}
+	return err
+}
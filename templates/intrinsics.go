@@ -0,0 +1,162 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// shortForms are the intrinsic functions that have a short form tag in YAML
+var shortForms = map[string]string{
+	"Ref":             "!Ref",
+	"Condition":       "!Condition",
+	"Fn::Base64":      "!Base64",
+	"Fn::Cidr":        "!Cidr",
+	"Fn::FindInMap":   "!FindInMap",
+	"Fn::GetAtt":      "!GetAtt",
+	"Fn::GetAZs":      "!GetAZs",
+	"Fn::ImportValue": "!ImportValue",
+	"Fn::Join":        "!Join",
+	"Fn::Select":      "!Select",
+	"Fn::Split":       "!Split",
+	"Fn::Sub":         "!Sub",
+	"Fn::Transform":   "!Transform",
+	"Fn::And":         "!And",
+	"Fn::Equals":      "!Equals",
+	"Fn::If":          "!If",
+	"Fn::Not":         "!Not",
+	"Fn::Or":          "!Or",
+}
+
+// functionName returns the long form name of a short form tag like !Ref
+func functionName(tag string) (string, bool) {
+	if !isCustomTag(tag) {
+		return "", false
+	}
+	switch name := tag[1:]; name {
+	case "Ref", "Condition":
+		return name, true
+	default:
+		return "Fn::" + name, true
+	}
+}
+
+func isCustomTag(tag string) bool {
+	return strings.HasPrefix(tag, "!") && !strings.HasPrefix(tag, "!!")
+}
+
+func defaultTag(kind yaml.Kind) string {
+	switch kind {
+	case yaml.SequenceNode:
+		return "!!seq"
+	case yaml.MappingNode:
+		return "!!map"
+	}
+	return "!!str"
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// expand returns a copy of a node with short form tags like !GetAtt a.b replaced with their long
+// form like {"Fn::GetAtt": ["a", "b"]}. Aliases are replaced with a copy of what they refer to.
+func expand(n *yaml.Node) (*yaml.Node, error) {
+	if n.Kind == yaml.AliasNode {
+		return expand(n.Alias)
+	}
+
+	c := *n
+	c.Anchor = ""
+	c.Content = nil
+
+	for _, child := range n.Content {
+		expanded, err := expand(child)
+		if err != nil {
+			return nil, err
+		}
+		c.Content = append(c.Content, expanded)
+	}
+
+	name, ok := functionName(n.Tag)
+	if !ok {
+		return &c, nil
+	}
+
+	// without the tag the node is the argument to the function
+	arg := &c
+	arg.Tag = defaultTag(arg.Kind)
+	arg.Style &^= yaml.TaggedStyle
+	arg.HeadComment, arg.LineComment, arg.FootComment = "", "", ""
+
+	if name == "Fn::GetAtt" && arg.Kind == yaml.ScalarNode {
+		parts := strings.SplitN(arg.Value, ".", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Line %d: !GetAtt %s must be in the form Resource.Attribute", n.Line, arg.Value)
+		}
+		arg = &yaml.Node{
+			Kind:    yaml.SequenceNode,
+			Tag:     "!!seq",
+			Style:   yaml.FlowStyle,
+			Content: []*yaml.Node{scalar(parts[0]), scalar(parts[1])},
+		}
+	}
+
+	return &yaml.Node{
+		Kind:        yaml.MappingNode,
+		Tag:         "!!map",
+		Line:        n.Line,
+		Column:      n.Column,
+		HeadComment: n.HeadComment,
+		LineComment: n.LineComment,
+		FootComment: n.FootComment,
+		Content:     []*yaml.Node{scalar(name), arg},
+	}, nil
+}
+
+// contract returns a copy of a node with long form intrinsic functions replaced with short form
+// tags where YAML allows it. A tag can't be applied to a node that already has one, so functions
+// that directly contain another short form function stay in their long form. Styles can be
+// stripped for templates that came from JSON, so that they are written in block style.
+func contract(n *yaml.Node, stripStyles bool) *yaml.Node {
+	c := *n
+	c.Content = nil
+
+	if stripStyles {
+		c.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+	}
+
+	for _, child := range n.Content {
+		c.Content = append(c.Content, contract(child, stripStyles))
+	}
+
+	if c.Kind != yaml.MappingNode || len(c.Content) != 2 {
+		return &c
+	}
+
+	key, arg := c.Content[0], c.Content[1]
+	tag, ok := shortForms[key.Value]
+	if !ok || isCustomTag(arg.Tag) {
+		return &c
+	}
+
+	// Ref and Condition only take a name
+	if (key.Value == "Ref" || key.Value == "Condition") && arg.Kind != yaml.ScalarNode {
+		return &c
+	}
+
+	short := *arg
+
+	if key.Value == "Fn::GetAtt" && arg.Kind == yaml.SequenceNode && len(arg.Content) == 2 &&
+		arg.Content[0].Kind == yaml.ScalarNode && arg.Content[1].Kind == yaml.ScalarNode {
+		short = *scalar(arg.Content[0].Value + "." + arg.Content[1].Value)
+	}
+
+	short.Tag = tag
+	short.HeadComment = c.HeadComment
+	short.LineComment = c.LineComment
+	short.FootComment = c.FootComment
+
+	return &short
+}
@@ -0,0 +1,99 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// writeJSON writes a node as indented JSON, keeping keys in the order they appear
+func writeJSON(w io.Writer, n *yaml.Node, indent string) error {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			_, err := io.WriteString(w, "null")
+			return err
+		}
+		return writeJSON(w, n.Content[0], indent)
+
+	case yaml.AliasNode:
+		return writeJSON(w, n.Alias, indent)
+
+	case yaml.MappingNode:
+		if len(n.Content) == 0 {
+			_, err := io.WriteString(w, "{}")
+			return err
+		}
+		io.WriteString(w, "{\n")
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				io.WriteString(w, ",\n")
+			}
+			fmt.Fprintf(w, "%s  %s: ", indent, jsonString(n.Content[i].Value))
+			if err := writeJSON(w, n.Content[i+1], indent+"  "); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(w, "\n%s}", indent)
+		return err
+
+	case yaml.SequenceNode:
+		if len(n.Content) == 0 {
+			_, err := io.WriteString(w, "[]")
+			return err
+		}
+		io.WriteString(w, "[\n")
+		for i, item := range n.Content {
+			if i > 0 {
+				io.WriteString(w, ",\n")
+			}
+			io.WriteString(w, indent+"  ")
+			if err := writeJSON(w, item, indent+"  "); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(w, "\n%s]", indent)
+		return err
+
+	case yaml.ScalarNode:
+		_, err := io.WriteString(w, jsonScalar(n))
+		return err
+	}
+
+	return fmt.Errorf("Line %d: unsupported node", n.Line)
+}
+
+// jsonScalar converts a YAML scalar to JSON, using the type that YAML resolves it to
+func jsonScalar(n *yaml.Node) string {
+	switch n.ShortTag() {
+	case "!!null":
+		return "null"
+
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err == nil {
+			return strconv.FormatBool(b)
+		}
+
+	// numbers that aren't valid JSON, like 012345678901 or 0o17, are kept as strings rather
+	// than losing digits by converting them
+	case "!!int", "!!float":
+		if json.Valid([]byte(n.Value)) {
+			return n.Value
+		}
+	}
+
+	return jsonString(n.Value)
+}
+
+func jsonString(s string) string {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
@@ -0,0 +1,76 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/template"
+)
+
+// OutputLookup returns the outputs of a stack for the output helper
+type OutputLookup func(stackName string) (map[string]string, error)
+
+// Render executes a template body as a Go text/template with vars as its data. Besides the
+// standard functions, templates can use env to read an environment variable, envDefault to read
+// one with a fallback and output to read an output of another stack. Referencing a var or an
+// environment variable that isn't set is an error.
+func Render(name, body string, vars map[string]interface{}, outputs OutputLookup) (string, error) {
+	t, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"env":        env,
+			"envDefault": envDefault,
+			"output":     outputFunc(outputs),
+		}).
+		Parse(body)
+	if err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	if err = t.Execute(buf, vars); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func env(name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("Environment variable %s isn't set", name)
+	}
+	return v, nil
+}
+
+func envDefault(name, fallback string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	return fallback
+}
+
+func outputFunc(lookup OutputLookup) func(stackName, key string) (string, error) {
+	cache := map[string]map[string]string{}
+
+	return func(stackName, key string) (string, error) {
+		if lookup == nil {
+			return "", fmt.Errorf("Stack outputs aren't available")
+		}
+
+		outputs, ok := cache[stackName]
+		if !ok {
+			var err error
+			if outputs, err = lookup(stackName); err != nil {
+				return "", err
+			}
+			cache[stackName] = outputs
+		}
+
+		v, ok := outputs[key]
+		if !ok {
+			return "", fmt.Errorf("Stack %s has no output %s", stackName, key)
+		}
+		return v, nil
+	}
+}
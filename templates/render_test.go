@@ -0,0 +1,68 @@
+package templates
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRenderingTemplate(t *testing.T) {
+	os.Setenv("PARFAIT_TEST_ENV", "prod")
+	defer os.Unsetenv("PARFAIT_TEST_ENV")
+
+	body := `Resources:
+{{- range .Queues }}
+  {{ . }}Queue:
+    Type: AWS::SQS::Queue
+{{- end }}
+Outputs:
+  Env:
+    Value: {{ env "PARFAIT_TEST_ENV" }}-{{ .Suffix }}
+  Region:
+    Value: {{ envDefault "PARFAIT_TEST_MISSING" "us-east-1" }}
+  Vpc:
+    Value: {{ output "network" "VpcId" }}
+    Description: In {{ output "network" "VpcId" }}
+`
+
+	lookups := 0
+	rendered, err := Render("test", body, map[string]interface{}{
+		"Queues": []interface{}{"Jobs", "Mail"},
+		"Suffix": "a",
+	}, func(stackName string) (map[string]string, error) {
+		lookups++
+		return map[string]string{"VpcId": "vpc-123"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Resources:
+  JobsQueue:
+    Type: AWS::SQS::Queue
+  MailQueue:
+    Type: AWS::SQS::Queue
+Outputs:
+  Env:
+    Value: prod-a
+  Region:
+    Value: us-east-1
+  Vpc:
+    Value: vpc-123
+    Description: In vpc-123
+`
+	if rendered != expected {
+		t.Fatalf("Expected %s, got %s", expected, rendered)
+	}
+
+	if lookups != 1 {
+		t.Fatalf("Expected 1 output lookup, got %d", lookups)
+	}
+}
+
+func TestRenderingTemplateWithMissingVar(t *testing.T) {
+	_, err := Render("test", "Value: {{ .Missing }}", map[string]interface{}{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "Missing") {
+		t.Fatalf("Expected an error about the missing var, got %v", err)
+	}
+}
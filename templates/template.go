@@ -0,0 +1,142 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+// Template is a parsed cloudformation template. Intrinsic functions are always held in their long
+// form, so a template parsed from YAML with !Ref or !GetAtt looks the same as one parsed from JSON.
+type Template struct {
+	Format Format
+	root   *yaml.Node
+}
+
+// Parse parses a JSON or YAML template, expanding short-form intrinsic functions like !Ref
+func Parse(body string) (*Template, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(body), &doc); err != nil {
+		return nil, err
+	}
+
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("Template must be a map")
+	}
+
+	format := YAML
+	if strings.HasPrefix(strings.TrimSpace(body), "{") {
+		format = JSON
+	}
+
+	root, err := expand(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{Format: format, root: root}, nil
+}
+
+// Root returns the root node of the template, with intrinsic functions in their long form
+func (t *Template) Root() *yaml.Node {
+	return t.root
+}
+
+// Section returns a top-level section of the template like Resources, or nil if it's missing
+func (t *Template) Section(name string) *yaml.Node {
+	return MapValue(t.root, name)
+}
+
+// Decode decodes the template into generic maps, slices and scalars
+func (t *Template) Decode() (map[string]interface{}, error) {
+	var v map[string]interface{}
+	if err := t.root.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// JSON writes the template as indented JSON, preserving the order of keys
+func (t *Template) JSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := writeJSON(buf, t.root, ""); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// YAML writes the template as YAML, using the short form of intrinsic functions where possible
+func (t *Template) YAML() ([]byte, error) {
+	node := contract(t.root, t.Format == JSON)
+	quoteOldBools(node)
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// quoteOldBools double quotes plain strings like yes, no, on and off, which YAML 1.1 readers
+// would otherwise parse as booleans
+func quoteOldBools(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!str" && n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) == 0 {
+		switch strings.ToLower(n.Value) {
+		case "y", "yes", "n", "no", "on", "off":
+			n.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	for _, child := range n.Content {
+		quoteOldBools(child)
+	}
+}
+
+// Write writes the template in the given format
+func (t *Template) Write(format Format) ([]byte, error) {
+	switch format {
+	case JSON:
+		return t.JSON()
+	case YAML:
+		return t.YAML()
+	}
+	return nil, fmt.Errorf("Unknown template format %q", format)
+}
+
+// MapValue returns the value of a key in a mapping node, or nil if the key is missing
+func MapValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// MapKeys returns the keys of a mapping node in order
+func MapKeys(n *yaml.Node) []string {
+	keys := []string{}
+	if n != nil && n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			keys = append(keys, n.Content[i].Value)
+		}
+	}
+	return keys
+}
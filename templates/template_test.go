@@ -0,0 +1,211 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertingYAMLToJSON(t *testing.T) {
+	tpl, err := Parse(`
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Sub "${AWS::StackName}-bucket"
+      Tags:
+        - Key: Arn
+          Value: !GetAtt Role.Arn
+        - Key: Zone
+          Value: !Select [0, !GetAZs ""]
+      Enabled: true
+      Count: 3
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := tpl.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+  "Resources": {
+    "Bucket": {
+      "Type": "AWS::S3::Bucket",
+      "Properties": {
+        "BucketName": {
+          "Fn::Sub": "${AWS::StackName}-bucket"
+        },
+        "Tags": [
+          {
+            "Key": "Arn",
+            "Value": {
+              "Fn::GetAtt": [
+                "Role",
+                "Arn"
+              ]
+            }
+          },
+          {
+            "Key": "Zone",
+            "Value": {
+              "Fn::Select": [
+                0,
+                {
+                  "Fn::GetAZs": ""
+                }
+              ]
+            }
+          }
+        ],
+        "Enabled": true,
+        "Count": 3
+      }
+    }
+  }
+}
+`
+	if string(out) != expected {
+		t.Fatalf("Expected %s, got %s", expected, out)
+	}
+}
+
+func TestConvertingJSONToYAML(t *testing.T) {
+	tpl, err := Parse(`{
+  "Resources": {
+    "Topic": {
+      "Type": "AWS::SNS::Topic",
+      "Properties": {
+        "TopicName": {"Ref": "Name"},
+        "DisplayName": {"Fn::GetAtt": ["Queue", "Arn"]},
+        "Subscription": {"Fn::If": ["HasQueue", [{"Endpoint": "x"}], {"Ref": "AWS::NoValue"}]},
+        "KmsMasterKeyId": {"Fn::Base64": {"Fn::Sub": "${Key}"}}
+      }
+    }
+  }
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tpl.Format != JSON {
+		t.Fatalf("Expected JSON format, got %s", tpl.Format)
+	}
+
+	out, err := tpl.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Resources:
+  Topic:
+    Type: AWS::SNS::Topic
+    Properties:
+      TopicName: !Ref Name
+      DisplayName: !GetAtt Queue.Arn
+      Subscription: !If
+        - HasQueue
+        - - Endpoint: x
+        - !Ref AWS::NoValue
+      KmsMasterKeyId:
+        Fn::Base64: !Sub ${Key}
+`
+	if string(out) != expected {
+		t.Fatalf("Expected %s, got %s", expected, out)
+	}
+}
+
+func TestRoundTrippingYAML(t *testing.T) {
+	body := `Conditions:
+  IsProd: !Equals [!Ref Env, prod]
+Outputs:
+  Arn:
+    Value: !GetAtt Bucket.Arn
+    Export:
+      Name: !Sub ${AWS::StackName}-Arn
+`
+	tpl, err := Parse(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := tpl.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out) != body {
+		t.Fatalf("Expected %s, got %s", body, out)
+	}
+}
+
+func TestParsingInvalidGetAtt(t *testing.T) {
+	_, err := Parse("Outputs:\n  Arn:\n    Value: !GetAtt Bucket\n")
+	if err == nil || !strings.Contains(err.Error(), "Resource.Attribute") {
+		t.Fatalf("Expected an error about the GetAtt form, got %v", err)
+	}
+}
+
+func TestConvertingNumbersThatArentJSON(t *testing.T) {
+	tpl, err := Parse(`
+Parameters:
+  AccountId:
+    Type: String
+    Default: 012345678901
+  Mode:
+    Type: Number
+    Default: 0o17
+  Ratio:
+    Type: Number
+    Default: 0.5
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := tpl.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{`"Default": "012345678901"`, `"Default": "0o17"`, `"Default": 0.5`} {
+		if !strings.Contains(string(out), expected) {
+			t.Errorf("Expected %s in %s", expected, out)
+		}
+	}
+}
+
+func TestConvertingYAML11BooleanStringsFromJSON(t *testing.T) {
+	tpl, err := Parse(`{
+  "Parameters": {
+    "Public": {"Type": "String", "AllowedValues": ["yes", "no", "On", "OFF", "maybe"]},
+    "AccountId": {"Type": "String", "Default": "012345678901"}
+  }
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := tpl.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Parameters:
+  Public:
+    Type: String
+    AllowedValues:
+      - "yes"
+      - "no"
+      - "On"
+      - "OFF"
+      - maybe
+  AccountId:
+    Type: String
+    Default: "012345678901"
+`
+	if string(out) != expected {
+		t.Fatalf("Expected %s, got %s", expected, out)
+	}
+}